@@ -1,5 +1,5 @@
 module _ // Auto generated by https://github.com/bwplotka/bingo. DO NOT EDIT
 
-go 1.17
+go 1.20
 
-require github.com/golangci/golangci-lint v1.45.2 // cmd/golangci-lint
+require github.com/golangci/golangci-lint v1.51.2 // cmd/golangci-lint
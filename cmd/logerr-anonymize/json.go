@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+// member is a key/value pair of a JSON object
+type member struct {
+	key   string
+	value interface{}
+}
+
+// object is a JSON object which keeps the order of its members
+type object []member
+
+// parseObject decodes a JSON object keeping the order of the members of all
+// nested objects. Numbers are kept as json.Number.
+func parseObject(b []byte) (object, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	v, err := parseValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, kverrors.New("unexpected data after JSON object")
+	}
+	obj, ok := v.(object)
+	if !ok {
+		return nil, kverrors.New("not a JSON object")
+	}
+	return obj, nil
+}
+
+func parseValue(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		obj := object{}
+		for dec.More() {
+			kt, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{key: kt.(string), value: v})
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []interface{}{}
+		for dec.More() {
+			v, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err := dec.Token()
+		return arr, err
+	default:
+		return t, nil
+	}
+}
+
+// MarshalJSON implements json.Marshaler keeping the order of the members
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := encode(&b, m.key); err != nil {
+			return nil, err
+		}
+		b.WriteByte(':')
+		if err := encode(&b, m.value); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// encode writes v without escaping HTML characters
+func encode(b *bytes.Buffer, v interface{}) error {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	// Encode terminates every value with a newline
+	b.Truncate(b.Len() - 1)
+	return nil
+}
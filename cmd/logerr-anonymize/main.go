@@ -0,0 +1,168 @@
+// Command logerr-anonymize scrubs sensitive data from logerr JSON log lines
+// before they are shared.
+//
+// Usage:
+//
+//	logerr-anonymize [flags] [file...]
+//
+// Lines are read from stdin if no file is given. The values of denylisted
+// keys are redacted, pseudonymized or dropped at any depth, including nested
+// _error objects. Value patterns, such as IP addresses, emails and tokens,
+// are applied to all string values except the timestamp, level, file:line
+// and component. Lines which are not JSON objects are scrubbed with the value
+// patterns and the key rules are applied to their key=value pairs.
+// Pseudonymized values are consistent for the same salt, so related lines can
+// still be correlated. A random salt is used if none is given.
+//
+// Unless -no-defaults is given, the keys password, passwd, secret, token,
+// authorization, apikey and api_key are redacted and all builtin patterns
+// (ipv4, ipv6, email and token) are pseudonymized.
+//
+// Rules can also be read from a JSON file:
+//
+//	{
+//	  "salt": "...",
+//	  "keys": {"user": "hash", "password": "redact", "debug": "drop"},
+//	  "patterns": [{"builtin": "ipv4"}, {"regex": "cluster-[0-9]+", "action": "redact"}]
+//	}
+//
+// Example:
+//
+//	LOGERR_ANONYMIZE_SALT=s3cr3t logerr-anonymize -hash-key user operator.log > operator.anon.log
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ViaQ/logerr/v2/internal/cli"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// saltEnv is the environment variable the salt is read from if -salt is
+// not given
+const saltEnv = "LOGERR_ANONYMIZE_SALT"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "logerr-anonymize: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var redactKeys, hashKeys, dropKeys, patterns, regexes cli.StringsFlag
+
+	fs := cli.NewFlagSet("logerr-anonymize", "[flags] [file...]", stderr)
+	configFile := fs.String("config", "", "read scrubbing rules from the JSON `file`")
+	fs.Var(&redactKeys, "redact-key", "redact the values of `key`, can be repeated")
+	fs.Var(&hashKeys, "hash-key", "pseudonymize the values of `key`, can be repeated")
+	fs.Var(&dropKeys, "drop-key", "drop the fields with `key`, can be repeated")
+	fs.Var(&patterns, "pattern", "pseudonymize the matches of the builtin pattern `name` (ipv4, ipv6, email, token), can be repeated")
+	fs.Var(&regexes, "regex", "pseudonymize the matches of `regex`, can be repeated")
+	salt := fs.String("salt", "", "`salt` of the pseudonymizing hashes, read from "+saltEnv+" if empty")
+	noDefaults := fs.Bool("no-defaults", false, "do not apply the default rules")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c := config{Keys: map[string]string{}}
+	if !*noDefaults {
+		for _, k := range defaultKeys {
+			c.Keys[k] = actionRedact
+		}
+		for _, name := range []string{"ipv4", "ipv6", "email", "token"} {
+			c.Patterns = append(c.Patterns, patternConfig{Builtin: name})
+		}
+	}
+	if *configFile != "" {
+		fc, err := loadConfig(*configFile)
+		if err != nil {
+			return err
+		}
+		c.Salt = fc.Salt
+		for k, action := range fc.Keys {
+			c.Keys[k] = action
+		}
+		c.Patterns = append(c.Patterns, fc.Patterns...)
+	}
+	for action, keys := range map[string][]string{actionRedact: redactKeys, actionHash: hashKeys, actionDrop: dropKeys} {
+		for _, k := range keys {
+			c.Keys[k] = action
+		}
+	}
+	for _, name := range patterns {
+		c.Patterns = append(c.Patterns, patternConfig{Builtin: name})
+	}
+	for _, expr := range regexes {
+		c.Patterns = append(c.Patterns, patternConfig{Regex: expr})
+	}
+
+	switch {
+	case *salt != "":
+		c.Salt = *salt
+	case os.Getenv(saltEnv) != "":
+		c.Salt = os.Getenv(saltEnv)
+	}
+	if c.Salt == "" {
+		salt, err := randomSalt()
+		if err != nil {
+			return err
+		}
+		c.Salt = salt
+		fmt.Fprintf(stderr, "logerr-anonymize: warning: no salt given, pseudonymized values are only consistent within this run\n")
+	}
+
+	s, err := newScrubber(c)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	if fs.NArg() == 0 {
+		return s.scrub(stdin, out)
+	}
+	for _, name := range fs.Args() {
+		if err := s.scrubFile(name, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// randomSalt returns a salt which is used if none is given so that
+// pseudonymized values cannot be guessed
+func randomSalt() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *scrubber) scrubFile(name string, w io.Writer) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.scrub(f, w)
+}
+
+func (s *scrubber) scrub(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), decode.MaxLineSize)
+	for scanner.Scan() {
+		if _, err := fmt.Fprintln(w, s.scrubLine(scanner.Text())); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func anonymize(t *testing.T, input string, args ...string) string {
+	out := bytes.NewBuffer(nil)
+	err := run(append([]string{"-salt", "salt"}, args...), strings.NewReader(input), out, bytes.NewBuffer(nil))
+	require.NoError(t, err)
+	return out.String()
+}
+
+func hashOf(v string) string {
+	return (&scrubber{salt: []byte("salt")}).hash(v)
+}
+
+func TestRun_DefaultRules(t *testing.T) {
+	in := `{"_ts":"2022-05-01T10:00:01Z","_file:line":"pkg/a.go:10","_level":"0","_component":"ctrl","_message":"connecting to 10.0.0.1","password":"hunter2","count":12345678901234567890,"_error":{"msg":"failed to notify admin@example.com","Token":"abc","cause":{"msg":"Authorization: Bearer abc.def"}}}
+panic: cannot reach 10.0.0.1
+`
+	expected := `{"_ts":"2022-05-01T10:00:01Z","_file:line":"pkg/a.go:10","_level":"0","_component":"ctrl","_message":"connecting to ` + hashOf("10.0.0.1") + `","password":"[REDACTED]","count":12345678901234567890,"_error":{"msg":"failed to notify ` + hashOf("admin@example.com") + `","Token":"[REDACTED]","cause":{"msg":"Authorization: ` + hashOf("Bearer abc.def") + `"}}}
+panic: cannot reach ` + hashOf("10.0.0.1") + `
+`
+	require.Equal(t, expected, anonymize(t, in))
+}
+
+func TestRun_KeyRules(t *testing.T) {
+	in := `{"_message":"hello","user":"bob","id":42,"debug":{"a":1},"items":[{"user":"alice"},"10.0.0.1"]}` + "\n"
+
+	expected := `{"_message":"hello","user":"` + hashOf("bob") + `","id":"` + hashOf("42") + `","items":[{"user":"` + hashOf("alice") + `"},"10.0.0.1"]}` + "\n"
+	require.Equal(t, expected, anonymize(t, in, "-no-defaults", "-hash-key", "user", "-hash-key", "id", "-drop-key", "debug"))
+}
+
+func TestRun_ProtectsBuiltinFields(t *testing.T) {
+	in := `{"_ts":"2022-05-01T10:00:01Z","_component":"10.0.0.1","_message":"10.0.0.1"}` + "\n"
+
+	expected := `{"_ts":"2022-05-01T10:00:01Z","_component":"10.0.0.1","_message":"` + hashOf("10.0.0.1") + `"}` + "\n"
+	require.Equal(t, expected, anonymize(t, in, "-no-defaults", "-pattern", "ipv4"))
+}
+
+func TestRun_Regex(t *testing.T) {
+	in := `{"_message":"reconciling cluster-42"}` + "\n"
+
+	expected := `{"_message":"reconciling ` + hashOf("cluster-42") + `"}` + "\n"
+	require.Equal(t, expected, anonymize(t, in, "-no-defaults", "-regex", `cluster-[0-9]+`))
+}
+
+func TestRun_Config(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(config, []byte(`{
+		"salt": "ignored",
+		"keys": {"user": "hash"},
+		"patterns": [{"regex": "cluster-[0-9]+", "action": "redact"}]
+	}`), 0o644))
+
+	in := `{"_message":"reconciling cluster-42","user":"bob"}` + "\n"
+
+	// -salt takes precedence over the salt of the config file
+	expected := `{"_message":"reconciling [REDACTED]","user":"` + hashOf("bob") + `"}` + "\n"
+	require.Equal(t, expected, anonymize(t, in, "-no-defaults", "-config", config))
+}
+
+func TestRun_ConsistentHashes(t *testing.T) {
+	in := `{"_message":"10.0.0.1"}` + "\n" + `{"_message":"10.0.0.1","ip":"10.0.0.2"}` + "\n"
+
+	out := strings.Split(anonymize(t, in), "\n")
+	require.Contains(t, out[0], hashOf("10.0.0.1"))
+	require.Contains(t, out[1], hashOf("10.0.0.1"))
+	require.Contains(t, out[1], hashOf("10.0.0.2"))
+	require.NotEqual(t, hashOf("10.0.0.1"), hashOf("10.0.0.2"))
+	require.NotEqual(t, hashOf("10.0.0.1"), (&scrubber{salt: []byte("other")}).hash("10.0.0.1"))
+}
+
+func TestBuiltinPatterns(t *testing.T) {
+	matches := map[string][]string{
+		"ipv4":  {"10.0.0.1", "255.255.255.255"},
+		"ipv6":  {"2001:db8:85a3:0:0:8a2e:370:7334", "fe80::1"},
+		"email": {"admin@example.com", "first.last+tag@sub.example.org"},
+		"token": {"Bearer abc.def", "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig", "sha256~abcdefghijklmnopqrstuvwxyz"},
+	}
+	nonMatches := map[string][]string{
+		"ipv4":  {"999.1.1.1", "v1.2.3"},
+		"ipv6":  {"10:11:12"},
+		"email": {"not an email", "user@localhost"},
+		"token": {"bearer", "sha256~short"},
+	}
+
+	for name, values := range matches {
+		s, err := newScrubber(config{Patterns: []patternConfig{{Builtin: name, Action: actionRedact}}})
+		require.NoError(t, err)
+		for _, v := range values {
+			require.Equal(t, redacted, strings.TrimSpace(s.scrubString(v)), "%s should match %s", name, v)
+		}
+	}
+	for name, values := range nonMatches {
+		s, err := newScrubber(config{Patterns: []patternConfig{{Builtin: name, Action: actionRedact}}})
+		require.NoError(t, err)
+		for _, v := range values {
+			require.Equal(t, v, s.scrubString(v), "%s should not match %s", name, v)
+		}
+	}
+}
+
+func TestNewScrubber_InvalidRules(t *testing.T) {
+	invalid := []config{
+		{Keys: map[string]string{"a": "encrypt"}},
+		{Patterns: []patternConfig{{Builtin: "phone"}}},
+		{Patterns: []patternConfig{{Regex: "("}}},
+		{Patterns: []patternConfig{{Regex: "a", Action: actionDrop}}},
+	}
+
+	for _, c := range invalid {
+		_, err := newScrubber(c)
+		require.Error(t, err, c)
+	}
+
+	_, err := newScrubber(invalid[0])
+	require.Equal(t, "encrypt", kverrors.KVs(err)["action"])
+	require.Equal(t, "a", kverrors.KVs(err)["key"])
+}
+
+func TestRun_TextKeyValues(t *testing.T) {
+	in := `login failed user=bob password=hunter2 token="a b" debug=1 mypassword=kept` + "\n"
+
+	expected := `login failed user=` + hashOf("bob") + ` password=[REDACTED] token="[REDACTED]"  mypassword=kept` + "\n"
+	require.Equal(t, expected, anonymize(t, in, "-hash-key", "user", "-drop-key", "debug"))
+}
+
+func TestRun_KeepsHTMLCharacters(t *testing.T) {
+	in := `{"_message":"a < b && c > d","nested":{"html":"<b>"}}` + "\n"
+
+	require.Equal(t, in, anonymize(t, in, "-no-defaults"))
+}
+
+func TestRun_RandomSalt(t *testing.T) {
+	t.Setenv(saltEnv, "")
+	in := `{"_message":"10.0.0.1"}` + "\n"
+
+	var outputs []string
+	for i := 0; i < 2; i++ {
+		out, stderr := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+		require.NoError(t, run(nil, strings.NewReader(in), out, stderr))
+		require.Contains(t, stderr.String(), "no salt given")
+		require.NotContains(t, out.String(), (&scrubber{}).hash("10.0.0.1"))
+		outputs = append(outputs, out.String())
+	}
+	require.NotEqual(t, outputs[0], outputs[1])
+}
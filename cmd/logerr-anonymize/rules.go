@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+// Actions applied to values matched by a rule
+const (
+	actionRedact = "redact"
+	actionHash   = "hash"
+	actionDrop   = "drop"
+)
+
+// redacted replaces redacted values
+const redacted = "[REDACTED]"
+
+// hashPrefix is the prefix of pseudonymized values
+const hashPrefix = "anon-"
+
+// builtinPatterns are value patterns which can be enabled by name
+var builtinPatterns = map[string]string{
+	"ipv4":  `\b(?:(?:25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])\b`,
+	"ipv6":  `(?i)\b(?:[0-9a-f]{1,4}:){7}[0-9a-f]{1,4}\b|\b(?:[0-9a-f]{1,4}:){1,7}:(?:[0-9a-f]{1,4}(?::[0-9a-f]{1,4}){0,6}\b)?`,
+	"email": `\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`,
+	"token": `(?i)\bbearer\s+[a-z0-9._~+/-]+=*|\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*|\bsha256~[A-Za-z0-9_-]{20,}`,
+}
+
+// defaultKeys are the keys redacted unless defaults are disabled
+var defaultKeys = []string{"password", "passwd", "secret", "token", "authorization", "apikey", "api_key"}
+
+// protectedKeys are builtin fields which value patterns are not applied to
+var protectedKeys = map[string]bool{
+	sink.TimeStampKey: true,
+	sink.LevelKey:     true,
+	sink.FileLineKey:  true,
+	sink.ComponentKey: true,
+}
+
+// config is the file format of the scrubbing rules
+type config struct {
+	// Salt makes the pseudonymizing hashes unguessable
+	Salt string `json:"salt"`
+	// Keys maps keys to the action applied to their values
+	Keys map[string]string `json:"keys"`
+	// Patterns are regular expressions applied to all string values
+	Patterns []patternConfig `json:"patterns"`
+}
+
+type patternConfig struct {
+	// Builtin is the name of a builtin pattern, exclusive with Regex
+	Builtin string `json:"builtin"`
+	Regex   string `json:"regex"`
+	// Action is redact or hash
+	Action string `json:"action"`
+}
+
+func loadConfig(name string) (config, error) {
+	var c config
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, kverrors.Wrap(err, "invalid config", "file", name)
+	}
+	return c, nil
+}
+
+// pattern replaces all matches of a regular expression
+type pattern struct {
+	re     *regexp.Regexp
+	action string
+}
+
+// scrubber applies scrubbing rules to log lines
+type scrubber struct {
+	salt     []byte
+	keys     map[string]string
+	patterns []pattern
+	// pairs matches key=value pairs of the keys in text lines
+	pairs *regexp.Regexp
+}
+
+func newScrubber(c config) (*scrubber, error) {
+	s := &scrubber{salt: []byte(c.Salt), keys: map[string]string{}}
+
+	for k, action := range c.Keys {
+		switch action {
+		case actionRedact, actionHash, actionDrop:
+		default:
+			return nil, kverrors.New("invalid action", "action", action, "key", k)
+		}
+		s.keys[strings.ToLower(k)] = action
+	}
+	s.pairs = pairsRegexp(s.keys)
+
+	for _, p := range c.Patterns {
+		expr := p.Regex
+		if p.Builtin != "" {
+			var ok bool
+			if expr, ok = builtinPatterns[p.Builtin]; !ok {
+				return nil, kverrors.New("unknown builtin pattern", "pattern", p.Builtin)
+			}
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, kverrors.Wrap(err, "invalid pattern", "pattern", expr)
+		}
+		action := p.Action
+		if action == "" {
+			action = actionHash
+		}
+		if action != actionRedact && action != actionHash {
+			return nil, kverrors.New("invalid action", "action", action, "pattern", expr)
+		}
+		s.patterns = append(s.patterns, pattern{re: re, action: action})
+	}
+
+	return s, nil
+}
+
+// pairsRegexp returns a regular expression matching key=value pairs of keys
+// or nil if there are no keys. Values are either quoted or end at the next
+// whitespace or separator.
+func pairsRegexp(keys map[string]string) *regexp.Regexp {
+	if len(keys) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(keys))
+	for k := range keys {
+		quoted = append(quoted, regexp.QuoteMeta(k))
+	}
+	sort.Strings(quoted)
+	return regexp.MustCompile(`(?i)(^|[^\w.-])(` + strings.Join(quoted, "|") + `)=("(?:[^"\\]|\\.)*"|[^\s,;&"]*)`)
+}
+
+// scrubLine scrubs a log line. Lines which are not JSON objects are scrubbed
+// with the key rules applied to key=value pairs and the value patterns.
+func (s *scrubber) scrubLine(line string) string {
+	obj, err := parseObject([]byte(line))
+	if err != nil {
+		return s.scrubText(line)
+	}
+
+	var b bytes.Buffer
+	if err := encode(&b, s.scrubObject(obj, true)); err != nil {
+		return s.scrubText(line)
+	}
+	return b.String()
+}
+
+// scrubText scrubs a line which is not a JSON object
+func (s *scrubber) scrubText(line string) string {
+	if s.pairs != nil {
+		line = s.pairs.ReplaceAllStringFunc(line, s.scrubPair)
+	}
+	return s.scrubString(line)
+}
+
+// scrubPair applies the key rules to a key=value pair matched by pairs
+func (s *scrubber) scrubPair(match string) string {
+	m := s.pairs.FindStringSubmatch(match)
+	prefix, key, value := m[1], m[2], m[3]
+
+	quote := ""
+	if strings.HasPrefix(value, `"`) {
+		quote = `"`
+		value = value[1 : len(value)-1]
+	}
+
+	switch s.keys[strings.ToLower(key)] {
+	case actionDrop:
+		return prefix
+	case actionRedact:
+		value = redacted
+	case actionHash:
+		value = s.hash(value)
+	}
+	return prefix + key + "=" + quote + value + quote
+}
+
+func (s *scrubber) scrubObject(obj object, topLevel bool) object {
+	res := make(object, 0, len(obj))
+	for _, m := range obj {
+		switch s.keys[strings.ToLower(m.key)] {
+		case actionDrop:
+			continue
+		case actionRedact:
+			m.value = redacted
+		case actionHash:
+			m.value = s.hash(valueString(m.value))
+		default:
+			if !topLevel || !protectedKeys[m.key] {
+				m.value = s.scrubValue(m.value)
+			}
+		}
+		res = append(res, m)
+	}
+	return res
+}
+
+func (s *scrubber) scrubValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case object:
+		return s.scrubObject(v, false)
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			res = append(res, s.scrubValue(item))
+		}
+		return res
+	case string:
+		return s.scrubString(v)
+	default:
+		return v
+	}
+}
+
+func (s *scrubber) scrubString(v string) string {
+	for _, p := range s.patterns {
+		v = p.re.ReplaceAllStringFunc(v, func(match string) string {
+			if p.action == actionRedact {
+				return redacted
+			}
+			return s.hash(match)
+		})
+	}
+	return v
+}
+
+// hash pseudonymizes v. Equal values result in equal hashes for the same
+// salt.
+func (s *scrubber) hash(v string) string {
+	mac := hmac.New(sha256.New, s.salt)
+	_, _ = mac.Write([]byte(v))
+	return hashPrefix + hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// valueString returns the text of v which is hashed
+func valueString(v interface{}) string {
+	if str, ok := v.(string); ok {
+		return str
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/internal/console"
+	"github.com/ViaQ/logerr/v2/internal/kv"
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// formatter writes an entry in an output format
+type formatter interface {
+	format(w io.Writer, e *decode.Entry) error
+}
+
+func newFormatter(name string) (formatter, error) {
+	switch name {
+	case "json":
+		return jsonFormatter{}, nil
+	case "logfmt":
+		return logfmtFormatter{}, nil
+	case "console":
+		return consoleFormatter{}, nil
+	case "otlp":
+		return otlpFormatter{}, nil
+	default:
+		return nil, kverrors.New("unknown format", "format", name)
+	}
+}
+
+// timestamp formats the timestamp of e like sink.TimestampFunc
+func timestamp(e *decode.Entry) string {
+	if e.Timestamp.IsZero() {
+		return ""
+	}
+	return e.Timestamp.UTC().Format(time.RFC3339Nano)
+}
+
+// jsonFormatter writes logerr JSON lines. Only the builtin fields of the
+// original line are written, so JSON lines which were not written by logerr do
+// not gain empty ones.
+type jsonFormatter struct{}
+
+func (jsonFormatter) format(w io.Writer, e *decode.Entry) error {
+	var original map[string]json.RawMessage
+	_ = json.Unmarshal(e.Raw, &original)
+
+	b := []byte{'{'}
+	for _, f := range []struct{ key, value string }{
+		{sink.TimeStampKey, timestamp(e)},
+		{sink.FileLineKey, e.FileLine()},
+		{sink.LevelKey, strconv.Itoa(e.Verbosity)},
+		{sink.ComponentKey, e.Component},
+		{sink.MessageKey, e.Message},
+	} {
+		if _, ok := original[f.key]; !ok {
+			continue
+		}
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendQuote(b, f.key)
+		b = append(b, ':')
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return err
+		}
+		b = append(b, value...)
+	}
+
+	context := make(map[string]interface{}, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		context[k] = v
+	}
+	if e.Error != nil {
+		context[sink.ErrorKey] = e.Error
+	}
+	fields, err := kv.AppendJSON(nil, context)
+	if err != nil {
+		return err
+	}
+	if len(fields) > 2 {
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+		b = append(b, fields[1:len(fields)-1]...)
+	}
+	b = append(b, '}', '\n')
+
+	_, err = w.Write(b)
+	return err
+}
+
+// Keys of the builtin fields in logfmt output
+const (
+	logfmtTimestampKey = "ts"
+	logfmtLevelKey     = "level"
+	logfmtComponentKey = "component"
+	logfmtMessageKey   = "msg"
+	logfmtFileLineKey  = "caller"
+	logfmtErrorKey     = "error"
+)
+
+// logfmtFormatter writes logfmt lines
+type logfmtFormatter struct{}
+
+func (logfmtFormatter) format(w io.Writer, e *decode.Entry) error {
+	var b strings.Builder
+
+	writePair(&b, logfmtTimestampKey, timestamp(e))
+	writePair(&b, logfmtLevelKey, strconv.Itoa(e.Verbosity))
+	writePair(&b, logfmtComponentKey, e.Component)
+	writePair(&b, logfmtMessageKey, e.Message)
+	if fl := e.FileLine(); fl != "" {
+		writePair(&b, logfmtFileLineKey, fl)
+	}
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writePair(&b, k, fieldString(e.Fields[k]))
+	}
+
+	if e.Error != nil {
+		writePair(&b, logfmtErrorKey, e.Error.Error())
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writePair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	b.WriteString(key)
+	b.WriteString("=")
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=\\") {
+		value = strconv.Quote(value)
+	}
+	b.WriteString(value)
+}
+
+func fieldString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return console.FormatValue(v)
+}
+
+// consoleFormatter writes the human-readable layout of logerr-pretty
+// without colors
+type consoleFormatter struct{}
+
+func (consoleFormatter) format(w io.Writer, e *decode.Entry) error {
+	_, err := io.WriteString(w, console.Printer{FileLine: true}.Format(e))
+	return err
+}
+
+// otlpFormatter writes one OTLP/JSON logs export request per line as
+// written by the file exporter of the OpenTelemetry collector
+type otlpFormatter struct{}
+
+func (otlpFormatter) format(w io.Writer, e *decode.Entry) error {
+	req := otlpRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: e.Component},
+				LogRecords: []otlpLogRecord{newLogRecord(e)},
+			}},
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(req)
+}
@@ -0,0 +1,120 @@
+// Command logerr-convert converts logerr JSON log lines into other formats.
+//
+// Usage:
+//
+//	logerr-convert [flags] [file...]
+//
+// Lines are read from stdin if no file is given. Supported output formats are
+// json (logerr JSON lines), logfmt, console (human-readable) and otlp
+// (OpenTelemetry OTLP/JSON, one export request per line).
+//
+// Example:
+//
+//	logerr-convert -to logfmt -rename ns=namespace -fields namespace,name archive.log
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ViaQ/logerr/v2/internal/cli"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "logerr-convert: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var renames cli.StringsFlag
+
+	fs := cli.NewFlagSet("logerr-convert", "[flags] [file...]", stderr)
+	to := fs.String("to", "logfmt", "output `format`: json, logfmt, console or otlp")
+	fs.Var(&renames, "rename", "rename the field `old=new`, can be repeated")
+	fields := fs.String("fields", "", "comma separated `list` of fields to keep, all fields are kept if empty")
+	skipInvalid := fs.Bool("skip-invalid", false, "drop lines which are not JSON log lines instead of copying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := newFormatter(*to)
+	if err != nil {
+		return err
+	}
+	m, err := newMapper(renames, *fields)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	c := &converter{
+		formatter:   f,
+		mapper:      m,
+		skipInvalid: *skipInvalid,
+		out:         out,
+	}
+
+	if fs.NArg() == 0 {
+		return c.convert(stdin)
+	}
+	for _, name := range fs.Args() {
+		if err := c.convertFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// converter reformats every entry of a stream
+type converter struct {
+	formatter   formatter
+	mapper      *mapper
+	skipInvalid bool
+	out         io.Writer
+}
+
+func (c *converter) convertFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.convert(f)
+}
+
+func (c *converter) convert(r io.Reader) error {
+	dec := decode.NewDecoder(r)
+	// keep integers which do not fit into a float64
+	dec.UseNumber()
+	for {
+		entry, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		var lineErr *decode.LineError
+		if errors.As(err, &lineErr) {
+			if !c.skipInvalid {
+				if _, err := fmt.Fprintln(c.out, lineErr.Text); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := c.formatter.format(c.out, c.mapper.apply(entry)); err != nil {
+			return err
+		}
+	}
+}
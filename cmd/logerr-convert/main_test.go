@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+const input = `{"_ts":"2022-05-01T10:11:12.123Z","_file:line":"pkg/a.go:10","_level":"2","_component":"ctrl","_message":"reconciling","ns":"default","count":3,"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","trace_sampled":true}
+not a log line
+{"_ts":"2022-05-01T10:11:14Z","_level":"0","_component":"webhook","_message":"failed","_error":{"msg":"outer","key":"value","cause":{"msg":"inner"}}}
+`
+
+func convert(t *testing.T, args ...string) string {
+	out := bytes.NewBuffer(nil)
+	err := run(args, strings.NewReader(input), out, bytes.NewBuffer(nil))
+	require.NoError(t, err)
+	return out.String()
+}
+
+func TestRun_JSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(convert(t, "-to", "json", "-skip-invalid")), "\n")
+	require.Len(t, lines, 2)
+
+	require.JSONEq(t, `{"_ts":"2022-05-01T10:11:12.123Z","_file:line":"pkg/a.go:10","_level":"2","_component":"ctrl","_message":"reconciling","ns":"default","count":3,"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","trace_sampled":true}`, lines[0])
+	require.JSONEq(t, `{"_ts":"2022-05-01T10:11:14Z","_level":"0","_component":"webhook","_message":"failed","_error":{"msg":"outer","key":"value","cause":{"msg":"inner"}}}`, lines[1])
+}
+
+func TestRun_JSONKeepsLines(t *testing.T) {
+	in := `{"_message":"no builtins","id":12345678901234567890,"small":1.5}` + "\n"
+
+	out := bytes.NewBuffer(nil)
+	require.NoError(t, run([]string{"-to", "json"}, strings.NewReader(in), out, bytes.NewBuffer(nil)))
+	require.Equal(t, `{"_message":"no builtins","id":12345678901234567890,"small":1.5}`+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, run([]string{"-to", "otlp"}, strings.NewReader(in), out, bytes.NewBuffer(nil)))
+	var req otlpRequest
+	require.NoError(t, json.Unmarshal(out.Bytes(), &req))
+	attrs := map[string]otlpValue{}
+	for _, a := range req.ResourceLogs[0].ScopeLogs[0].LogRecords[0].Attributes {
+		attrs[a.Key] = a.Value
+	}
+	require.Equal(t, "12345678901234567890", *attrs["id"].StringValue)
+	require.Equal(t, 1.5, *attrs["small"].DoubleValue)
+}
+
+func TestRun_Logfmt(t *testing.T) {
+	expected := `ts=2022-05-01T10:11:12.123Z level=2 component=ctrl msg=reconciling caller=pkg/a.go:10 count=3 ns=default span_id=00f067aa0ba902b7 trace_id=4bf92f3577b34da6a3ce929d0e0e4736 trace_sampled=true
+not a log line
+ts=2022-05-01T10:11:14Z level=0 component=webhook msg=failed error="outer: inner"
+`
+	require.Equal(t, expected, convert(t, "-to", "logfmt"))
+}
+
+func TestRun_Console(t *testing.T) {
+	out := convert(t, "-to", "console", "-skip-invalid")
+
+	require.Contains(t, out, "2022-05-01T10:11:12.123Z V2 ctrl pkg/a.go:10 reconciling count=3 ns=default")
+	require.Contains(t, out, "    error: outer key=value\n    caused by: inner\n")
+	require.NotContains(t, out, "not a log line")
+}
+
+func TestRun_OTLP(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(convert(t, "-to", "otlp", "-skip-invalid")), "\n")
+	require.Len(t, lines, 2)
+
+	var req otlpRequest
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &req))
+	require.Equal(t, "ctrl", req.ResourceLogs[0].ScopeLogs[0].Scope.Name)
+
+	record := req.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	require.Equal(t, "1651399872123000000", record.TimeUnixNano)
+	require.Equal(t, severityDebug, record.SeverityNumber)
+	require.Equal(t, "reconciling", *record.Body.StringValue)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record.TraceID)
+	require.Equal(t, "00f067aa0ba902b7", record.SpanID)
+	require.Equal(t, 1, record.Flags)
+
+	attrs := map[string]otlpValue{}
+	for _, a := range record.Attributes {
+		attrs[a.Key] = a.Value
+	}
+	require.Equal(t, "pkg/a.go", *attrs[attrFilePath].StringValue)
+	require.Equal(t, "10", *attrs[attrLineNumber].IntValue)
+	require.Equal(t, "3", *attrs["count"].IntValue)
+	require.Equal(t, "default", *attrs["ns"].StringValue)
+	require.NotContains(t, attrs, "trace_id")
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &req))
+	record = req.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	require.Equal(t, severityError, record.SeverityNumber)
+	require.Equal(t, "ERROR", record.SeverityText)
+	for _, a := range record.Attributes {
+		if a.Key == attrExceptionMessage {
+			require.Equal(t, "outer: inner", *a.Value.StringValue)
+		}
+	}
+}
+
+func TestRun_RenameAndProject(t *testing.T) {
+	out := convert(t, "-to", "logfmt", "-skip-invalid", "-rename", "ns=namespace", "-fields", "namespace,count")
+
+	expected := `ts=2022-05-01T10:11:12.123Z level=2 component=ctrl msg=reconciling caller=pkg/a.go:10 count=3 namespace=default
+ts=2022-05-01T10:11:14Z level=0 component=webhook msg=failed error="outer: inner"
+`
+	require.Equal(t, expected, out)
+}
+
+func TestRun_InvalidFlags(t *testing.T) {
+	invalid := [][]string{
+		{"-to", "xml"},
+		{"-rename", "novalue"},
+		{"-rename", "=new"},
+	}
+
+	for _, args := range invalid {
+		err := run(args, strings.NewReader(""), bytes.NewBuffer(nil), bytes.NewBuffer(nil))
+		require.Error(t, err, args)
+	}
+
+	err := run([]string{"-to", "xml"}, strings.NewReader(""), bytes.NewBuffer(nil), bytes.NewBuffer(nil))
+	require.Equal(t, "xml", kverrors.KVs(err)["format"])
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// mapper renames and projects the fields of entries. Builtin fields are
+// never renamed or dropped.
+type mapper struct {
+	renames map[string]string
+	keep    map[string]bool
+}
+
+func newMapper(renames []string, fields string) (*mapper, error) {
+	m := &mapper{renames: map[string]string{}}
+	for _, r := range renames {
+		parts := strings.SplitN(r, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, kverrors.New("invalid rename, expected old=new", "rename", r)
+		}
+		m.renames[parts[0]] = parts[1]
+	}
+
+	if fields != "" {
+		m.keep = map[string]bool{}
+		for _, f := range strings.Split(fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				m.keep[f] = true
+			}
+		}
+	}
+	return m, nil
+}
+
+// apply returns a copy of e with renamed and projected fields
+func (m *mapper) apply(e *decode.Entry) *decode.Entry {
+	if len(m.renames) == 0 && m.keep == nil {
+		return e
+	}
+
+	mapped := *e
+	mapped.Fields = make(map[string]interface{}, len(e.Fields))
+	for k, v := range e.Fields {
+		if renamed, ok := m.renames[k]; ok {
+			k = renamed
+		}
+		if m.keep != nil && !m.keep[k] {
+			continue
+		}
+		mapped.Fields[k] = v
+	}
+	return &mapped
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// Severity numbers of the OpenTelemetry log data model
+const (
+	severityDebug = 5
+	severityInfo  = 9
+	severityError = 17
+)
+
+// Attribute keys of the OpenTelemetry semantic conventions
+const (
+	attrFilePath         = "code.filepath"
+	attrLineNumber       = "code.lineno"
+	attrExceptionMessage = "exception.message"
+	attrVerbosity        = "logerr.verbosity"
+	attrError            = "logerr.error"
+)
+
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  struct{}        `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name,omitempty"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano,omitempty"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           otlpValue       `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes,omitempty"`
+	TraceID        string          `json:"traceId,omitempty"`
+	SpanID         string          `json:"spanId,omitempty"`
+	Flags          int             `json:"flags,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string        `json:"stringValue,omitempty"`
+	BoolValue   *bool          `json:"boolValue,omitempty"`
+	IntValue    *string        `json:"intValue,omitempty"`
+	DoubleValue *float64       `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArray     `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKeyValues `json:"kvlistValue,omitempty"`
+}
+
+type otlpArray struct {
+	Values []otlpValue `json:"values"`
+}
+
+type otlpKeyValues struct {
+	Values []otlpAttribute `json:"values"`
+}
+
+// newLogRecord maps the fields of a sink.Line to an OTLP log record. Error
+// lines have the severity ERROR, developer logs (verbosity above 1) DEBUG
+// and all other lines INFO.
+func newLogRecord(e *decode.Entry) otlpLogRecord {
+	r := otlpLogRecord{
+		SeverityNumber: severityInfo,
+		SeverityText:   "INFO",
+		Body:           stringValue(e.Message),
+	}
+	switch {
+	case e.Error != nil:
+		r.SeverityNumber, r.SeverityText = severityError, "ERROR"
+	case e.Verbosity > 1:
+		r.SeverityNumber, r.SeverityText = severityDebug, "DEBUG"
+	}
+	if !e.Timestamp.IsZero() {
+		r.TimeUnixNano = strconv.FormatInt(e.Timestamp.UnixNano(), 10)
+	}
+
+	fields := make(map[string]interface{}, len(e.Fields))
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	if traceID, ok := fields[sink.TraceIDKey].(string); ok {
+		r.TraceID = traceID
+		delete(fields, sink.TraceIDKey)
+	}
+	if spanID, ok := fields[sink.SpanIDKey].(string); ok {
+		r.SpanID = spanID
+		delete(fields, sink.SpanIDKey)
+	}
+	if sampled, ok := fields[sink.TraceSampledKey].(bool); ok {
+		if sampled {
+			r.Flags = 1
+		}
+		delete(fields, sink.TraceSampledKey)
+	}
+
+	r.Attributes = append(r.Attributes, otlpAttribute{Key: attrVerbosity, Value: anyValue(float64(e.Verbosity))})
+	if e.File != "" {
+		r.Attributes = append(r.Attributes,
+			otlpAttribute{Key: attrFilePath, Value: stringValue(e.File)},
+			otlpAttribute{Key: attrLineNumber, Value: anyValue(float64(e.Line))},
+		)
+	}
+	if e.Error != nil {
+		r.Attributes = append(r.Attributes,
+			otlpAttribute{Key: attrExceptionMessage, Value: stringValue(e.Error.Error())},
+			otlpAttribute{Key: attrError, Value: anyValue(errorValue(e.Error))},
+		)
+	}
+	r.Attributes = append(r.Attributes, attributes(fields)...)
+
+	return r
+}
+
+// errorValue converts err to the generic JSON representation of its
+// marshaled form
+func errorValue(err error) interface{} {
+	var v interface{}
+	b, mErr := json.Marshal(err)
+	if mErr != nil || json.Unmarshal(b, &v) != nil {
+		return err.Error()
+	}
+	return v
+}
+
+func attributes(m map[string]interface{}) []otlpAttribute {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]otlpAttribute, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, otlpAttribute{Key: k, Value: anyValue(m[k])})
+	}
+	return attrs
+}
+
+func stringValue(s string) otlpValue {
+	return otlpValue{StringValue: &s}
+}
+
+// anyValue converts a decoded JSON value to an OTLP AnyValue. Integral
+// numbers are converted to intValue if they fit into an int64.
+func anyValue(v interface{}) otlpValue {
+	switch v := v.(type) {
+	case nil:
+		return otlpValue{}
+	case string:
+		return stringValue(v)
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			i := v.String()
+			return otlpValue{IntValue: &i}
+		}
+		// integers beyond int64 are kept exactly as a string
+		if f, err := v.Float64(); err == nil && strings.ContainsAny(v.String(), ".eE") {
+			return otlpValue{DoubleValue: &f}
+		}
+		return stringValue(v.String())
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			i := strconv.FormatInt(int64(v), 10)
+			return otlpValue{IntValue: &i}
+		}
+		return otlpValue{DoubleValue: &v}
+	case []interface{}:
+		values := make([]otlpValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, anyValue(item))
+		}
+		return otlpValue{ArrayValue: &otlpArray{Values: values}}
+	case map[string]interface{}:
+		return otlpValue{KvlistValue: &otlpKeyValues{Values: attributes(v)}}
+	default:
+		return stringValue(fieldString(v))
+	}
+}
@@ -0,0 +1,160 @@
+// Command logerr-merge merges several logerr JSON log streams into one,
+// ordered by timestamp.
+//
+// Usage:
+//
+//	logerr-merge [flags] [name=]file...
+//
+// Every line is tagged with its source, which is the given name or the base
+// name of the file without extension. Lines which are not JSON log lines are
+// kept next to the preceding line of the same file.
+//
+// Example:
+//
+//	logerr-merge -pretty operator=operator.log webhook=webhook.log
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/internal/cli"
+	"github.com/ViaQ/logerr/v2/internal/console"
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "logerr-merge: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := cli.NewFlagSet("logerr-merge", "[flags] [name=]file...", stderr)
+	window := fs.Duration("window", 2*time.Second, "maximum `duration` by which lines of a single file may be out of order")
+	sourceKey := fs.String("source-key", "_source", "`key` of the field which is set on every JSON log line, replacing an existing value")
+	pretty := fs.Bool("pretty", false, "render the merged stream in a human-readable layout")
+	color := fs.Bool("color", false, "colorize the human-readable layout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return kverrors.New("no files given")
+	}
+
+	var inputs []*input
+	for _, arg := range fs.Args() {
+		source, name := parseArg(arg)
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		inputs = append(inputs, newInput(source, f))
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	var emit func(*line) error
+	if *pretty {
+		emit = prettyEmitter(out, inputs, *color)
+	} else {
+		emit = jsonEmitter(out, *sourceKey)
+	}
+
+	return merge(inputs, *window, emit)
+}
+
+// parseArg splits an argument of the form [name=]file
+func parseArg(arg string) (string, string) {
+	if i := strings.Index(arg, "="); i > 0 {
+		return arg[:i], arg[i+1:]
+	}
+	base := filepath.Base(arg)
+	return strings.TrimSuffix(base, filepath.Ext(base)), arg
+}
+
+// jsonEmitter writes JSON log lines unchanged except for the source field
+// which is set, see withField. Other lines are written unchanged.
+func jsonEmitter(w io.Writer, sourceKey string) func(*line) error {
+	return func(l *line) error {
+		text := l.text
+		if l.entry != nil {
+			text = withField(strings.TrimSpace(text), sourceKey, l.source)
+		}
+		_, err := fmt.Fprintln(w, text)
+		return err
+	}
+}
+
+// withField sets a string field of a JSON object. The value of an existing
+// field is replaced in place, otherwise the field is appended.
+func withField(object, key, value string) string {
+	if start, end, ok := fieldValue(object, key); ok {
+		encoded, _ := json.Marshal(value)
+		return object[:start] + string(encoded) + object[end:]
+	}
+
+	field, _ := json.Marshal(map[string]string{key: value})
+	body := strings.TrimSpace(object[1 : len(object)-1])
+	if body == "" {
+		return string(field)
+	}
+	return fmt.Sprintf("{%s,%s", body, field[1:])
+}
+
+// fieldValue returns the offsets of the value of the top level field key of
+// a JSON object and whether the object has the field
+func fieldValue(object, key string) (int, int, bool) {
+	dec := json.NewDecoder(strings.NewReader(object))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return 0, 0, false
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return 0, 0, false
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return 0, 0, false
+		}
+		if t == key {
+			end := int(dec.InputOffset())
+			return end - len(bytes.TrimSpace(raw)), end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// prettyEmitter renders lines in the layout of logerr-pretty prefixed with
+// their source
+func prettyEmitter(w io.Writer, inputs []*input, color bool) func(*line) error {
+	width := 0
+	for _, in := range inputs {
+		if len(in.source) > width {
+			width = len(in.source)
+		}
+	}
+	printer := console.Printer{Color: color}
+
+	return func(l *line) error {
+		text := printer.FormatRaw(l.text)
+		if l.entry != nil {
+			text = printer.Format(l.entry)
+		}
+		_, err := fmt.Fprintf(w, "%-*s | %s", width, l.source, text)
+		return err
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func entry(ts, msg string) string {
+	return `{"_ts":"2022-05-01T10:00:` + ts + `Z","_message":"` + msg + `"}`
+}
+
+func mergeStrings(t *testing.T, window time.Duration, streams map[string][]string) []string {
+	var inputs []*input
+	for _, source := range []string{"a", "b", "c"} {
+		if lines, ok := streams[source]; ok {
+			inputs = append(inputs, newInput(source, strings.NewReader(strings.Join(lines, "\n"))))
+		}
+	}
+
+	var res []string
+	err := merge(inputs, window, func(l *line) error {
+		msg := l.text
+		if l.entry != nil {
+			msg = l.entry.Message
+		}
+		res = append(res, l.source+":"+msg)
+		return nil
+	})
+	require.NoError(t, err)
+	return res
+}
+
+func TestMerge_OrdersByTimestamp(t *testing.T) {
+	res := mergeStrings(t, 0, map[string][]string{
+		"a": {entry("01", "a1"), entry("04", "a2")},
+		"b": {entry("02", "b1"), entry("03", "b2"), entry("05", "b3")},
+		"c": {entry("00", "c1")},
+	})
+
+	require.Equal(t, []string{"c:c1", "a:a1", "b:b1", "b:b2", "a:a2", "b:b3"}, res)
+}
+
+func TestMerge_ReordersWithinWindow(t *testing.T) {
+	res := mergeStrings(t, 2*time.Second, map[string][]string{
+		"a": {entry("01", "a1"), entry("04", "a2"), entry("08", "a3")},
+		"b": {entry("03", "b1"), entry("02", "b0"), entry("06", "b2")},
+	})
+
+	require.Equal(t, []string{"a:a1", "b:b0", "b:b1", "a:a2", "b:b2", "a:a3"}, res)
+}
+
+func TestMerge_KeepsLinesWithoutTimestampInPlace(t *testing.T) {
+	res := mergeStrings(t, 0, map[string][]string{
+		"a": {entry("01", "a1"), entry("03", "a2")},
+		"b": {entry("02", "b1"), "panic: something went wrong", "", `{"_message":"no timestamp"}`},
+	})
+
+	require.Equal(t, []string{"a:a1", "b:b1", "b:panic: something went wrong", "b:no timestamp", "a:a2"}, res)
+}
+
+func TestRun_TagsSource(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "operator.log")
+	b := filepath.Join(dir, "webhook.log")
+	require.NoError(t, os.WriteFile(a, []byte(entry("01", "a1")+"\n"+entry("03", "a2")+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(b, []byte(entry("02", "b1")+"\nnot a log line\n"), 0o644))
+
+	out := bytes.NewBuffer(nil)
+	require.NoError(t, run([]string{a, "hook=" + b}, out, bytes.NewBuffer(nil)))
+
+	expected := `{"_ts":"2022-05-01T10:00:01Z","_message":"a1","_source":"operator"}
+{"_ts":"2022-05-01T10:00:02Z","_message":"b1","_source":"hook"}
+not a log line
+{"_ts":"2022-05-01T10:00:03Z","_message":"a2","_source":"operator"}
+`
+	require.Equal(t, expected, out.String())
+
+	out.Reset()
+	require.NoError(t, run([]string{"-pretty", a, "hook=" + b}, out, bytes.NewBuffer(nil)))
+
+	expected = `operator | 2022-05-01T10:00:01.000Z V0 a1
+hook     | 2022-05-01T10:00:02.000Z V0 b1
+hook     | not a log line
+operator | 2022-05-01T10:00:03.000Z V0 a2
+`
+	require.Equal(t, expected, out.String())
+}
+
+func TestRun_RequiresFiles(t *testing.T) {
+	require.Error(t, run(nil, bytes.NewBuffer(nil), bytes.NewBuffer(nil)))
+}
+
+func TestWithField(t *testing.T) {
+	require.Equal(t, `{"a":1,"src":"x"}`, withField(`{"a":1}`, "src", "x"))
+	require.Equal(t, `{"src":"x"}`, withField(`{ }`, "src", "x"))
+	require.Equal(t, `{"src":"x","a":1}`, withField(`{"src":"old","a":1}`, "src", "x"))
+	require.Equal(t, `{"a":{"src":1}, "src" : "x" }`, withField(`{"a":{"src":1}, "src" : {"b":[1]} }`, "src", "x"))
+}
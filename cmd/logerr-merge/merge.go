@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// line is a line of an input together with its decoded entry
+type line struct {
+	source string
+	text   string
+	// entry is nil if the line is not a JSON log line
+	entry *decode.Entry
+	// ts is the timestamp used for ordering. Lines without a timestamp
+	// inherit the timestamp of the previous line of the same input.
+	ts time.Time
+	// seq keeps the order of lines with the same timestamp stable
+	seq int
+}
+
+// input reads the lines of a single stream
+type input struct {
+	source  string
+	scanner *bufio.Scanner
+	last    time.Time
+	done    bool
+}
+
+func newInput(source string, r io.Reader) *input {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), decode.MaxLineSize)
+	return &input{source: source, scanner: scanner}
+}
+
+// next reads the next non-empty line. It returns false at the end of the
+// stream.
+func (in *input) next() (*line, bool, error) {
+	for in.scanner.Scan() {
+		text := in.scanner.Text()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		l := &line{source: in.source, text: text, ts: in.last}
+		if entry, err := decode.Parse([]byte(text)); err == nil {
+			l.entry = entry
+			if !entry.Timestamp.IsZero() {
+				l.ts = entry.Timestamp
+			}
+		}
+		in.last = l.ts
+		return l, true, nil
+	}
+	in.done = true
+	return nil, false, in.scanner.Err()
+}
+
+// lineHeap orders lines by timestamp and sequence
+type lineHeap []*line
+
+func (h lineHeap) Len() int { return len(h) }
+
+func (h lineHeap) Less(i, j int) bool {
+	if !h[i].ts.Equal(h[j].ts) {
+		return h[i].ts.Before(h[j].ts)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h lineHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *lineHeap) Push(x interface{}) { *h = append(*h, x.(*line)) }
+
+func (h *lineHeap) Pop() interface{} {
+	old := *h
+	l := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return l
+}
+
+// merge calls emit for the lines of all inputs ordered by their timestamps.
+// Every input may be unsorted by up to window: a line is only emitted once
+// every input which is not exhausted has read past its timestamp plus window.
+func merge(inputs []*input, window time.Duration, emit func(*line) error) error {
+	var (
+		pending lineHeap
+		seq     int
+	)
+
+	read := func(in *input) error {
+		l, ok, err := in.next()
+		if err != nil || !ok {
+			return err
+		}
+		l.seq = seq
+		seq++
+		heap.Push(&pending, l)
+		return nil
+	}
+
+	for _, in := range inputs {
+		if err := read(in); err != nil {
+			return err
+		}
+	}
+
+	for {
+		// advance the input which is the furthest behind
+		var behind *input
+		for _, in := range inputs {
+			if !in.done && (behind == nil || in.last.Before(behind.last)) {
+				behind = in
+			}
+		}
+		if behind == nil {
+			break
+		}
+		if err := read(behind); err != nil {
+			return err
+		}
+
+		watermark := behind.last
+		for _, in := range inputs {
+			if !in.done && in.last.Before(watermark) {
+				watermark = in.last
+			}
+		}
+		for pending.Len() > 0 && !pending[0].ts.Add(window).After(watermark) {
+			if err := emit(heap.Pop(&pending).(*line)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for pending.Len() > 0 {
+		if err := emit(heap.Pop(&pending).(*line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
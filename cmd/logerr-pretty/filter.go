@@ -0,0 +1,116 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/internal/console"
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// filter decides which entries are shown
+type filter struct {
+	level      int
+	errorsOnly bool
+	components []string
+	message    *regexp.Regexp
+	predicates []predicate
+}
+
+// predicate matches the value of a field
+type predicate struct {
+	key    string
+	value  string
+	negate bool
+}
+
+func newFilter(level int, errorsOnly bool, components []string, message string, predicates []string) (*filter, error) {
+	for _, c := range components {
+		if _, err := path.Match(c, ""); err != nil {
+			return nil, kverrors.Wrap(err, "invalid component glob", "glob", c)
+		}
+	}
+
+	re, err := compile(message)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "invalid message regex", "regex", message)
+	}
+
+	f := &filter{
+		level:      level,
+		errorsOnly: errorsOnly,
+		components: components,
+		message:    re,
+	}
+	for _, expr := range predicates {
+		p, err := parsePredicate(expr)
+		if err != nil {
+			return nil, err
+		}
+		f.predicates = append(f.predicates, p)
+	}
+	return f, nil
+}
+
+func parsePredicate(expr string) (predicate, error) {
+	if i := strings.Index(expr, "!="); i > 0 {
+		return predicate{key: expr[:i], value: expr[i+2:], negate: true}, nil
+	}
+	if i := strings.Index(expr, "="); i > 0 {
+		return predicate{key: expr[:i], value: expr[i+1:]}, nil
+	}
+	return predicate{}, kverrors.New("invalid predicate, expected key=value or key!=value", "predicate", expr)
+}
+
+func (f *filter) match(e *decode.Entry) bool {
+	if f.level >= 0 && e.Verbosity > f.level {
+		return false
+	}
+	if f.errorsOnly && e.Error == nil {
+		return false
+	}
+	if len(f.components) > 0 && !matchAny(f.components, e.Component) {
+		return false
+	}
+	if f.message != nil && !f.message.MatchString(e.Message) {
+		return false
+	}
+	for _, p := range f.predicates {
+		if !p.match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p predicate) match(e *decode.Entry) bool {
+	v, ok := e.Fields[p.key]
+	equal := ok && valueString(v) == p.value
+	return equal != p.negate
+}
+
+func matchAny(globs []string, s string) bool {
+	for _, g := range globs {
+		if ok, _ := path.Match(g, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func valueString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return console.FormatValue(v)
+}
+
+// compile is regexp.Compile which accepts an empty expression as no filter
+func compile(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expr)
+}
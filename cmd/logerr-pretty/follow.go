@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// followFiles follows all files concurrently and calls fn for every line.
+// Calls of fn are serialized. It only returns if a file cannot be followed,
+// in which case the other files are no longer followed.
+func followFiles(names []string, interval time.Duration, fn func(string)) error {
+	var (
+		mtx  sync.Mutex
+		stop = make(chan struct{})
+		errs = make(chan error, len(names))
+	)
+	defer close(stop)
+
+	for _, name := range names {
+		go func(name string) {
+			errs <- follow(name, interval, stop, func(line string) {
+				mtx.Lock()
+				defer mtx.Unlock()
+				fn(line)
+			})
+		}(name)
+	}
+
+	for range names {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// follow reads name line by line and keeps polling it for new lines every
+// interval. If the file is rotated (replaced by a new file) or truncated, it
+// is read again from the start. follow returns when stop is closed.
+func follow(name string, interval time.Duration, stop <-chan struct{}, fn func(string)) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+
+	var (
+		r       = bufio.NewReader(f)
+		partial strings.Builder
+		offset  int64
+	)
+
+	for {
+		line, err := r.ReadString('\n')
+		offset += int64(len(line))
+		partial.WriteString(line)
+
+		if err == nil {
+			fn(strings.TrimRight(partial.String(), "\r\n"))
+			partial.Reset()
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(interval):
+		}
+
+		current, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		latest, err := os.Stat(name)
+		switch {
+		case err != nil:
+			// the file is being rotated, keep reading the old one
+		case !os.SameFile(current, latest):
+			nf, err := os.Open(name)
+			if err != nil {
+				continue
+			}
+			// drain what was appended to the old file before it was rotated
+			if rest, _ := io.ReadAll(r); len(rest) > 0 {
+				partial.Write(rest)
+			}
+			if partial.Len() > 0 {
+				fn(strings.TrimRight(partial.String(), "\r\n"))
+				partial.Reset()
+			}
+			f.Close()
+			f, r, offset = nf, bufio.NewReader(nf), 0
+		case latest.Size() < offset:
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			r.Reset(f)
+			partial.Reset()
+			offset = 0
+		}
+	}
+}
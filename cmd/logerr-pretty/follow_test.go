@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollow_SurvivesRotationAndTruncation(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(name, []byte("first\n"), 0o644))
+
+	var (
+		mtx   sync.Mutex
+		lines []string
+		stop  = make(chan struct{})
+		done  = make(chan error)
+	)
+	received := func() []string {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]string(nil), lines...)
+	}
+
+	go func() {
+		done <- follow(name, 10*time.Millisecond, stop, func(line string) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			lines = append(lines, line)
+		})
+	}()
+
+	appendTo(t, name, "second\n")
+	require.Eventually(t, func() bool { return len(received()) == 2 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, os.Rename(name, name+".1"))
+	appendTo(t, name+".1", "third\n")
+	require.NoError(t, os.WriteFile(name, []byte("fourth\n"), 0o644))
+	require.Eventually(t, func() bool { return len(received()) == 4 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(name, []byte("fifth\n"), 0o644))
+	require.Eventually(t, func() bool { return len(received()) == 5 }, time.Second, 5*time.Millisecond)
+
+	close(stop)
+	require.NoError(t, <-done)
+	require.Equal(t, []string{"first", "second", "third", "fourth", "fifth"}, received())
+}
+
+func TestFollowFiles_ReturnsIfAFileCannotBeFollowed(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.log")
+	require.NoError(t, os.WriteFile(good, []byte("first\n"), 0o644))
+
+	done := make(chan error)
+	go func() {
+		done <- followFiles([]string{filepath.Join(dir, "missing.log"), good}, 10*time.Millisecond, func(string) {})
+	}()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, os.ErrNotExist)
+	case <-time.After(time.Second):
+		t.Fatal("followFiles did not return")
+	}
+}
+
+func appendTo(t *testing.T, name, s string) {
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.WriteString(s)
+	require.NoError(t, err)
+}
@@ -0,0 +1,164 @@
+// Command logerr-pretty renders logerr JSON log lines as readable, colorized
+// output.
+//
+// Usage:
+//
+//	logerr-pretty [flags] [file...]
+//
+// Lines are read from stdin if no file is given. Lines which are not JSON log
+// lines are printed unchanged.
+//
+// Example:
+//
+//	kubectl logs deploy/my-operator | logerr-pretty -component 'controller_*' -where namespace=default
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/internal/cli"
+	"github.com/ViaQ/logerr/v2/internal/console"
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "logerr-pretty: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		components cli.StringsFlag
+		predicates cli.StringsFlag
+	)
+
+	fs := cli.NewFlagSet("logerr-pretty", "[flags] [file...]", os.Stderr)
+	level := fs.Int("level", -1, "only show entries with a verbosity of at most `level`, -1 shows all entries")
+	errorsOnly := fs.Bool("errors", false, "only show entries with an error")
+	fs.Var(&components, "component", "only show entries whose component matches the `glob`, can be repeated")
+	message := fs.String("message", "", "only show entries whose message matches the `regex`")
+	fs.Var(&predicates, "where", "only show entries with a field matching `key=value` or key!=value, can be repeated")
+	raw := fs.Bool("raw", true, "show lines which are not JSON log lines")
+	follow := fs.Bool("f", false, "follow the files as they grow, surviving file rotation")
+	color := fs.String("color", "auto", "colorize output: `auto`, always or never")
+	fileLine := fs.Bool("file-line", false, "show the file:line of entries")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := newFilter(*level, *errorsOnly, components, *message, predicates)
+	if err != nil {
+		return err
+	}
+
+	useColor, err := colorEnabled(*color, stdout)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	p := &prettifier{
+		printer: console.Printer{Color: useColor, FileLine: *fileLine},
+		filter:  f,
+		raw:     *raw,
+		out:     out,
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		return readLines(stdin, p.handle)
+	}
+
+	if *follow {
+		// flush after every line so followed output shows up immediately
+		p.flush = true
+		return followFiles(files, time.Second/4, p.handle)
+	}
+
+	for _, name := range files {
+		if err := readFile(name, p.handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prettifier renders every line which passes the filter
+type prettifier struct {
+	printer console.Printer
+	filter  *filter
+	raw     bool
+	flush   bool
+	out     *bufio.Writer
+}
+
+func (p *prettifier) handle(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	entry, err := decode.Parse([]byte(line))
+	switch {
+	case err != nil && p.raw:
+		_, _ = p.out.WriteString(p.printer.FormatRaw(line))
+	case err == nil && p.filter.match(entry):
+		_, _ = p.out.WriteString(p.printer.Format(entry))
+	}
+
+	if p.flush {
+		_ = p.out.Flush()
+	}
+}
+
+func readFile(name string, fn func(string)) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return readLines(f, fn)
+}
+
+func readLines(r io.Reader, fn func(string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), decode.MaxLineSize)
+	for scanner.Scan() {
+		fn(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+func colorEnabled(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false, nil
+		}
+		f, ok := w.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			return false, nil
+		}
+		return fi.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, kverrors.New("invalid color mode", "mode", mode)
+	}
+}
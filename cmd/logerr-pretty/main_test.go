@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const input = `{"_ts":"2022-05-01T10:11:12.123Z","_level":"0","_component":"controller_cluster","_message":"reconciling","namespace":"default"}
+not a log line
+{"_ts":"2022-05-01T10:11:13.000Z","_level":"2","_component":"controller_cluster","_message":"details","namespace":"other"}
+{"_ts":"2022-05-01T10:11:14.000Z","_level":"0","_component":"webhook","_message":"failed","_error":{"msg":"outer","key":"value","cause":{"msg":"inner"}}}
+`
+
+func TestRun_RendersEntries(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+
+	err := run([]string{"-color", "never"}, strings.NewReader(input), out)
+	require.NoError(t, err)
+
+	expected := `2022-05-01T10:11:12.123Z V0 controller_cluster reconciling namespace=default
+not a log line
+2022-05-01T10:11:13.000Z V2 controller_cluster details namespace=other
+2022-05-01T10:11:14.000Z V0 webhook failed
+    error: outer key=value
+    caused by: inner
+`
+	require.Equal(t, expected, out.String())
+}
+
+func TestRun_Filters(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{args: []string{"-level", "1"}, expected: []string{"reconciling", "failed"}},
+		{args: []string{"-errors"}, expected: []string{"failed"}},
+		{args: []string{"-component", "controller_*"}, expected: []string{"reconciling", "details"}},
+		{args: []string{"-component", "web*", "-component", "none"}, expected: []string{"failed"}},
+		{args: []string{"-message", "^(re|de)"}, expected: []string{"reconciling", "details"}},
+		{args: []string{"-where", "namespace=default"}, expected: []string{"reconciling"}},
+		{args: []string{"-where", "namespace!=default"}, expected: []string{"details", "failed"}},
+	}
+
+	for _, tc := range tests {
+		out := bytes.NewBuffer(nil)
+		args := append([]string{"-color", "never", "-raw=false"}, tc.args...)
+
+		err := run(args, strings.NewReader(input), out)
+		require.NoError(t, err)
+
+		var messages []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if strings.HasPrefix(line, " ") {
+				continue
+			}
+			messages = append(messages, strings.Fields(line)[3])
+		}
+		require.Equal(t, tc.expected, messages, tc.args)
+	}
+}
+
+func TestRun_InvalidFlags(t *testing.T) {
+	invalid := [][]string{
+		{"-message", "("},
+		{"-component", "["},
+		{"-where", "novalue"},
+		{"-color", "sometimes"},
+	}
+
+	for _, args := range invalid {
+		err := run(args, strings.NewReader(""), bytes.NewBuffer(nil))
+		require.Error(t, err, args)
+	}
+}
+
+func TestRun_RendersForeignCauses(t *testing.T) {
+	in := `{"_ts":"2022-05-01T10:11:14.000Z","_level":"0","_message":"failed","_error":{"msg":"outer","cause":{"msg":"decode","error_type":"*fmt.wrapError","cause":{"msg":"inner","file":"a.json","cause":"EOF"}}}}
+`
+	out := bytes.NewBuffer(nil)
+
+	err := run([]string{"-color", "never"}, strings.NewReader(in), out)
+	require.NoError(t, err)
+
+	expected := `2022-05-01T10:11:14.000Z V0 failed
+    error: outer
+    caused by: decode
+    caused by: inner file=a.json
+    caused by: EOF
+`
+	require.Equal(t, expected, out.String())
+}
@@ -0,0 +1,106 @@
+// Command logerr-stats summarizes logerr JSON log lines.
+//
+// Usage:
+//
+//	logerr-stats [flags] [file...]
+//
+// Lines are read from stdin if no file is given. The report contains the
+// number of entries per component, level and message, the most frequent
+// errors grouped by their root cause, the noisiest call sites and a histogram
+// of entries over time.
+//
+// Example:
+//
+//	kubectl logs deploy/my-operator | logerr-stats -top 5
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/internal/cli"
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "logerr-stats: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := cli.NewFlagSet("logerr-stats", "[flags] [file...]", stderr)
+	top := fs.Int("top", 10, "show the `n` most frequent items of every statistic, 0 shows all")
+	bucket := fs.Duration("bucket", 0, "`size` of the histogram buckets of at least 1s, chosen automatically if 0 or if it would produce too many buckets")
+	format := fs.String("format", "text", "output `format`: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *bucket < 0 || (*bucket > 0 && *bucket < time.Second) {
+		return kverrors.New("bucket size must be 0 or at least 1s", "bucket", bucket.String())
+	}
+
+	var write func(io.Writer, report) error
+	switch *format {
+	case "text":
+		write = writeText
+	case "json":
+		write = writeJSON
+	default:
+		return kverrors.New("unknown format", "format", *format)
+	}
+
+	s := newStats(*bucket)
+	if fs.NArg() == 0 {
+		if err := s.read(stdin); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		if err := s.readFile(name); err != nil {
+			return err
+		}
+	}
+
+	out := bufio.NewWriter(stdout)
+	if err := write(out, s.report(*top)); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+func (s *stats) readFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.read(f)
+}
+
+func (s *stats) read(r io.Reader) error {
+	dec := decode.NewDecoder(r)
+	for {
+		entry, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		var lineErr *decode.LineError
+		if errors.As(err, &lineErr) {
+			s.invalid++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.add(entry)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const input = `{"_ts":"2022-05-01T10:00:05Z","_file:line":"pkg/a.go:10","_level":"2","_component":"ctrl","_message":"reconciling"}
+{"_ts":"2022-05-01T10:00:30Z","_file:line":"pkg/a.go:10","_level":"2","_component":"ctrl","_message":"reconciling"}
+{"_ts":"2022-05-01T10:01:10Z","_file:line":"pkg/b.go:20","_level":"2","_component":"ctrl","_message":"failed to reconcile","_error":{"msg":"failed to get secret","cause":{"msg":"connection refused"}}}
+not a log line
+{"_ts":"2022-05-01T10:03:00Z","_level":"0","_component":"webhook","_message":"request failed","_error":{"msg":"failed to admit","cause":{"msg":"connection refused"}}}
+{"_ts":"2022-05-01T10:03:01Z","_level":"0","_component":"webhook","_message":"request failed","_error":{"msg":"invalid object"}}
+`
+
+func collect(t *testing.T, args ...string) report {
+	out := bytes.NewBuffer(nil)
+	err := run(append(args, "-format", "json"), strings.NewReader(input), out, bytes.NewBuffer(nil))
+	require.NoError(t, err)
+
+	var r report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &r))
+	return r
+}
+
+func TestRun_Counts(t *testing.T) {
+	r := collect(t)
+
+	require.Equal(t, 5, r.Entries)
+	require.Equal(t, 1, r.InvalidLines)
+	require.Equal(t, time.Date(2022, 5, 1, 10, 0, 5, 0, time.UTC), *r.First)
+	require.Equal(t, time.Date(2022, 5, 1, 10, 3, 1, 0, time.UTC), *r.Last)
+	require.Equal(t, []count{{"ctrl", 3}, {"webhook", 2}}, r.Components)
+	require.Equal(t, []count{{"V2", 3}, {"V0", 2}}, r.Levels)
+	require.Equal(t, []count{{"reconciling", 2}, {"request failed", 2}, {"failed to reconcile", 1}}, r.Messages)
+	require.Equal(t, []count{{"pkg/a.go:10", 2}, {"pkg/b.go:20", 1}}, r.CallSites)
+}
+
+func TestRun_ErrorsByRootCause(t *testing.T) {
+	r := collect(t)
+
+	require.Equal(t, []errorGroup{
+		{
+			RootCause: "connection refused",
+			Count:     2,
+			Messages:  []count{{"failed to admit", 1}, {"failed to get secret", 1}},
+		},
+		{
+			RootCause: "invalid object",
+			Count:     1,
+			Messages:  []count{{"invalid object", 1}},
+		},
+	}, r.Errors)
+}
+
+func TestRun_Top(t *testing.T) {
+	r := collect(t, "-top", "1")
+
+	require.Equal(t, []count{{"ctrl", 3}}, r.Components)
+	require.Len(t, r.Errors, 1)
+	// levels are never truncated
+	require.Len(t, r.Levels, 2)
+}
+
+func TestRun_Histogram(t *testing.T) {
+	r := collect(t, "-bucket", "1m")
+
+	start := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.Equal(t, []bucket{
+		{Start: start, Count: 2},
+		{Start: start.Add(time.Minute), Count: 1},
+		{Start: start.Add(2 * time.Minute), Count: 0},
+		{Start: start.Add(3 * time.Minute), Count: 2},
+	}, r.Histogram)
+}
+
+func TestBucketSize(t *testing.T) {
+	require.Equal(t, time.Second, bucketSize(30*time.Second))
+	require.Equal(t, 5*time.Second, bucketSize(3*time.Minute))
+	require.Equal(t, 30*time.Minute, bucketSize(24*time.Hour))
+	require.Equal(t, 16*7*24*time.Hour, bucketSize(10*365*24*time.Hour))
+	require.Less(t, int64(100*365*24*time.Hour/bucketSize(100*365*24*time.Hour)), int64(maxBuckets))
+}
+
+func TestRun_HistogramBounded(t *testing.T) {
+	r := collect(t, "-bucket", "1s")
+
+	require.LessOrEqual(t, len(r.Histogram), maxBuckets)
+	require.Equal(t, 5*time.Second, r.Histogram[1].Start.Sub(r.Histogram[0].Start))
+
+	for _, size := range []string{"500ms", "-1m"} {
+		err := run([]string{"-bucket", size}, strings.NewReader(input), bytes.NewBuffer(nil), bytes.NewBuffer(nil))
+		require.Error(t, err, size)
+	}
+}
+
+func TestRun_Text(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	err := run([]string{"-bucket", "1m"}, strings.NewReader(input), out, bytes.NewBuffer(nil))
+	require.NoError(t, err)
+
+	require.Contains(t, out.String(), "Entries: 5\n")
+	require.Contains(t, out.String(), "Errors by root cause:\n      2  connection refused\n      1    failed to admit\n")
+	require.Contains(t, out.String(), "2022-05-01T10:02:00Z       0\n")
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// maxBarWidth is the width of the largest bar of the histogram
+	maxBarWidth = 50
+	// maxBuckets is the maximum number of buckets of the histogram
+	maxBuckets = 60
+	// countWidth is the minimum width of counts in the text report
+	countWidth = 7
+)
+
+// bucketSizes are the candidates if the bucket size is chosen automatically
+var bucketSizes = []time.Duration{
+	time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+	time.Minute, 5 * time.Minute, 10 * time.Minute, 30 * time.Minute,
+	time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour,
+}
+
+// report is the summary of the collected statistics
+type report struct {
+	Entries      int          `json:"entries"`
+	InvalidLines int          `json:"invalidLines"`
+	First        *time.Time   `json:"first,omitempty"`
+	Last         *time.Time   `json:"last,omitempty"`
+	Components   []count      `json:"components"`
+	Levels       []count      `json:"levels"`
+	Messages     []count      `json:"messages"`
+	Errors       []errorGroup `json:"errors"`
+	CallSites    []count      `json:"callSites"`
+	Histogram    []bucket     `json:"histogram"`
+}
+
+// errorGroup are the error entries with the same root cause
+type errorGroup struct {
+	RootCause string  `json:"rootCause"`
+	Count     int     `json:"count"`
+	Messages  []count `json:"messages"`
+}
+
+// bucket is the number of entries in a time interval
+type bucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// report returns the n most frequent items of every statistic
+func (s *stats) report(n int) report {
+	r := report{
+		Entries:      s.entries,
+		InvalidLines: s.invalid,
+		Components:   s.components.top(n),
+		Levels:       s.levels.top(0),
+		Messages:     s.messages.top(n),
+		CallSites:    s.fileLines.top(n),
+	}
+	if !s.first.IsZero() {
+		first, last := s.first, s.last
+		r.First, r.Last = &first, &last
+	}
+
+	roots := counter{}
+	for root, rc := range s.rootCauses {
+		roots[root] = rc.count
+	}
+	for _, c := range roots.top(n) {
+		r.Errors = append(r.Errors, errorGroup{
+			RootCause: c.Key,
+			Count:     c.Count,
+			Messages:  s.rootCauses[c.Key].messages.top(n),
+		})
+	}
+
+	if !s.first.IsZero() {
+		// sizes which split the span into too many buckets are raised
+		size := bucketSize(s.last.Sub(s.first))
+		if s.bucket > size {
+			size = s.bucket
+		}
+		counts := map[time.Time]int{}
+		for t, c := range s.histogram {
+			counts[t.Truncate(size)] += c
+		}
+		// fill empty buckets so gaps are visible
+		for t := s.first.UTC().Truncate(size); !t.After(s.last); t = t.Add(size) {
+			r.Histogram = append(r.Histogram, bucket{Start: t, Count: counts[t]})
+		}
+	}
+
+	return r
+}
+
+// bucketSize returns the smallest bucket size which splits span into at most
+// maxBuckets buckets. Spans beyond the largest candidate get multiples of it.
+func bucketSize(span time.Duration) time.Duration {
+	for _, size := range bucketSizes {
+		if span/size < maxBuckets {
+			return size
+		}
+	}
+	size := bucketSizes[len(bucketSizes)-1]
+	for span/size >= maxBuckets {
+		size *= 2
+	}
+	return size
+}
+
+func writeJSON(w io.Writer, r report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func writeText(w io.Writer, r report) error {
+	fmt.Fprintf(w, "Entries: %d\n", r.Entries)
+	if r.InvalidLines > 0 {
+		fmt.Fprintf(w, "Invalid lines: %d\n", r.InvalidLines)
+	}
+	if r.First != nil {
+		fmt.Fprintf(w, "Time range: %s - %s\n", r.First.Format(time.RFC3339), r.Last.Format(time.RFC3339))
+	}
+
+	writeCounts(w, "Components", r.Components)
+	writeCounts(w, "Levels", r.Levels)
+	writeCounts(w, "Messages", r.Messages)
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintf(w, "\nErrors by root cause:\n")
+		for _, g := range r.Errors {
+			fmt.Fprintf(w, "%*d  %s\n", countWidth, g.Count, display(g.RootCause))
+			for _, m := range g.Messages {
+				fmt.Fprintf(w, "%*d    %s\n", countWidth, m.Count, display(m.Key))
+			}
+		}
+	}
+
+	writeCounts(w, "Call sites", r.CallSites)
+
+	return writeHistogram(w, r.Histogram)
+}
+
+func writeCounts(w io.Writer, title string, counts []count) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, c := range counts {
+		fmt.Fprintf(w, "%*d  %s\n", countWidth, c.Count, display(c.Key))
+	}
+}
+
+func writeHistogram(w io.Writer, buckets []bucket) error {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	max := 0
+	for _, b := range buckets {
+		if b.Count > max {
+			max = b.Count
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\nHistogram:\n"); err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		width := 0
+		if max > 0 {
+			width = (b.Count*maxBarWidth + max - 1) / max
+		}
+		line := fmt.Sprintf("%s %*d %s", b.Start.Format(time.RFC3339), countWidth, b.Count, strings.Repeat("#", width))
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// display renders empty keys visibly
+func display(key string) string {
+	if key == "" {
+		return "(none)"
+	}
+	return key
+}
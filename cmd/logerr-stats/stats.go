@@ -0,0 +1,111 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// count is the number of occurrences of a key
+type count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// counter counts occurrences of keys
+type counter map[string]int
+
+// top returns the n most frequent keys. Keys with the same count are sorted
+// alphabetically. All keys are returned if n is not positive.
+func (c counter) top(n int) []count {
+	res := make([]count, 0, len(c))
+	for k, v := range c {
+		res = append(res, count{Key: k, Count: v})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Key < res[j].Key
+	})
+	if n > 0 && len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+// rootCause groups error entries by the message of their root cause
+type rootCause struct {
+	count    int
+	messages counter
+}
+
+// stats collects statistics about entries
+type stats struct {
+	// bucket is the size of the histogram buckets, it is chosen
+	// automatically if not positive
+	bucket time.Duration
+
+	entries    int
+	invalid    int
+	first      time.Time
+	last       time.Time
+	components counter
+	levels     counter
+	messages   counter
+	fileLines  counter
+	rootCauses map[string]*rootCause
+	// histogram counts the entries per second
+	histogram map[time.Time]int
+}
+
+func newStats(bucket time.Duration) *stats {
+	return &stats{
+		bucket:     bucket,
+		components: counter{},
+		levels:     counter{},
+		messages:   counter{},
+		fileLines:  counter{},
+		rootCauses: map[string]*rootCause{},
+		histogram:  map[time.Time]int{},
+	}
+}
+
+func (s *stats) add(e *decode.Entry) {
+	s.entries++
+	s.components[e.Component]++
+	s.levels[levelName(e.Verbosity)]++
+	s.messages[e.Message]++
+	if fl := e.FileLine(); fl != "" {
+		s.fileLines[fl]++
+	}
+
+	if e.Error != nil {
+		root := kverrors.Message(kverrors.Root(e.Error))
+		rc, ok := s.rootCauses[root]
+		if !ok {
+			rc = &rootCause{messages: counter{}}
+			s.rootCauses[root] = rc
+		}
+		rc.count++
+		rc.messages[kverrors.Message(e.Error)]++
+	}
+
+	if e.Timestamp.IsZero() {
+		return
+	}
+	if s.first.IsZero() || e.Timestamp.Before(s.first) {
+		s.first = e.Timestamp
+	}
+	if e.Timestamp.After(s.last) {
+		s.last = e.Timestamp
+	}
+	s.histogram[e.Timestamp.UTC().Truncate(time.Second)]++
+}
+
+func levelName(v int) string {
+	return "V" + strconv.Itoa(v)
+}
@@ -0,0 +1,50 @@
+// Package field provides typed key/value pairs for loggers created with the
+// log package and errors created with the kverrors package. Fields can be
+// mixed freely with alternating keys and values:
+//
+//	logger.Info("reconciled", field.String("namespace", ns), field.Duration("took", d), "attempt", n)
+//
+// Loggers keep the value of a field unboxed until it is encoded, without
+// reflection, to JSON.
+package field
+
+import (
+	"time"
+
+	"github.com/ViaQ/logerr/v2/internal/kv"
+)
+
+// Field is a typed key/value pair
+type Field = kv.Field
+
+// String creates a string field
+func String(key, value string) Field {
+	return kv.StringField(key, value)
+}
+
+// Int creates an int field
+func Int(key string, value int) Field {
+	return kv.IntField(key, value)
+}
+
+// Duration creates a time.Duration field. It is encoded as nanoseconds like
+// with encoding/json.
+func Duration(key string, value time.Duration) Field {
+	return kv.DurationField(key, value)
+}
+
+// Bool creates a bool field
+func Bool(key string, value bool) Field {
+	return kv.BoolField(key, value)
+}
+
+// Err creates an error field. Errors which do not implement json.Marshaler
+// are encoded as their message.
+func Err(key string, err error) Field {
+	return kv.ErrField(key, err)
+}
+
+// Any creates a field of any type
+func Any(key string, value interface{}) Field {
+	return kv.AnyField(key, value)
+}
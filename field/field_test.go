@@ -0,0 +1,62 @@
+package field_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/field"
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log/logtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestField_Value(t *testing.T) {
+	tests := []struct {
+		field    field.Field
+		expected interface{}
+	}{
+		{field: field.String("key", "value"), expected: "value"},
+		{field: field.Int("key", -3), expected: -3},
+		{field: field.Duration("key", time.Second), expected: time.Second},
+		{field: field.Bool("key", true), expected: true},
+		{field: field.Bool("key", false), expected: false},
+		{field: field.Err("key", io.EOF), expected: io.EOF},
+		{field: field.Err("key", nil), expected: nil},
+		{field: field.Any("key", []int{1}), expected: []int{1}},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, "key", tc.field.Key())
+		require.Equal(t, tc.expected, tc.field.Value())
+	}
+}
+
+func TestField_Logger(t *testing.T) {
+	logger, r := logtest.NewLogger("")
+	logger.WithValues(field.String("namespace", "default"), "cluster", "prod").
+		Info("reconciled", field.Int("attempt", 3), "ready", true, field.Duration("took", 1500*time.Millisecond), field.Err("last_error", io.EOF))
+
+	logtest.AssertLogged(t, r, "reconciled",
+		"namespace", "default",
+		"cluster", "prod",
+		"attempt", 3,
+		"ready", true,
+		"took", 1500000000,
+		"last_error", "EOF",
+	)
+}
+
+func TestField_KVError(t *testing.T) {
+	err := kverrors.Wrap(io.EOF, "failed to read", field.String("file", "a.json"), "offset", 10, field.Bool("retry", false))
+
+	require.Equal(t, map[string]interface{}{
+		kverrors.MessageKey: "failed to read",
+		kverrors.CauseKey:   io.EOF,
+		"file":              "a.json",
+		"offset":            10,
+		"retry":             false,
+	}, kverrors.KVs(err))
+	require.True(t, errors.Is(err, io.EOF))
+}
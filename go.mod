@@ -1,6 +1,6 @@
 module github.com/ViaQ/logerr/v2
 
-go 1.17
+go 1.20
 
 require (
 	github.com/go-logr/logr v1.2.3
@@ -0,0 +1,35 @@
+// Package cli contains helpers shared by the logerr commands
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// StringsFlag is a flag which can be repeated
+type StringsFlag []string
+
+func (s *StringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set appends v to the values of the flag
+func (s *StringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// NewFlagSet creates a flag set for the command name which writes errors and
+// its usage to output. args describes the arguments of the command in the
+// usage, for example "[flags] [file...]".
+func NewFlagSet(name, args string, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -0,0 +1,156 @@
+// Package console renders decoded log entries in a human-readable layout
+package console
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// ANSI escape sequences used to colorize output
+const (
+	reset  = "\x1b[0m"
+	dim    = "\x1b[2m"
+	bold   = "\x1b[1m"
+	red    = "\x1b[31m"
+	yellow = "\x1b[33m"
+	blue   = "\x1b[34m"
+	cyan   = "\x1b[36m"
+)
+
+// TimeFormat is the default format of timestamps
+const TimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// Printer renders entries in a human-readable layout:
+//
+//	<timestamp> V<level> <component> <message> <key>=<value>...
+//	    error: <message> <key>=<value>...
+//	    caused by: <message> <key>=<value>...
+type Printer struct {
+	// Color enables ANSI colors
+	Color bool
+	// TimeFormat is the format of timestamps. TimeFormat is used if empty.
+	TimeFormat string
+	// FileLine includes the file:line of the entry if it is known
+	FileLine bool
+}
+
+// Format renders e including a trailing newline
+func (p Printer) Format(e *decode.Entry) string {
+	var b strings.Builder
+
+	tf := p.TimeFormat
+	if tf == "" {
+		tf = TimeFormat
+	}
+	ts := strings.Repeat(" ", len(tf))
+	if !e.Timestamp.IsZero() {
+		ts = e.Timestamp.Format(tf)
+	}
+	b.WriteString(p.colorize(dim, ts))
+	b.WriteString(" ")
+	b.WriteString(p.colorize(yellow, "V"+strconv.Itoa(e.Verbosity)))
+	if e.Component != "" {
+		b.WriteString(" ")
+		b.WriteString(p.colorize(cyan, e.Component))
+	}
+	if p.FileLine && e.File != "" {
+		b.WriteString(" ")
+		b.WriteString(p.colorize(dim, e.FileLine()))
+	}
+	b.WriteString(" ")
+	if e.Error != nil {
+		b.WriteString(p.colorize(bold+red, e.Message))
+	} else {
+		b.WriteString(p.colorize(bold, e.Message))
+	}
+	p.writeFields(&b, e.Fields)
+	b.WriteString("\n")
+
+	label := "error"
+	for err := e.Error; err != nil; err = kverrors.Unwrap(err) {
+		b.WriteString("    ")
+		b.WriteString(p.colorize(red, label+":"))
+		b.WriteString(" ")
+		b.WriteString(kverrors.LinkMessage(err))
+		p.writeFields(&b, errorFields(err))
+		b.WriteString("\n")
+		label = "caused by"
+	}
+
+	return b.String()
+}
+
+// FormatRaw renders a line which could not be decoded including a trailing
+// newline
+func (p Printer) FormatRaw(line string) string {
+	return p.colorize(dim, line) + "\n"
+}
+
+func (p Printer) writeFields(b *strings.Builder, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b.WriteString(" ")
+		b.WriteString(p.colorize(blue, k+"="))
+		b.WriteString(FormatValue(fields[k]))
+	}
+}
+
+func (p Printer) colorize(color, s string) string {
+	if !p.Color {
+		return s
+	}
+	return color + s + reset
+}
+
+// errorFields returns the key/values of err without the message and cause
+func errorFields(err error) map[string]interface{} {
+	kve, ok := err.(*kverrors.KVError)
+	if !ok {
+		return nil
+	}
+	kvs := kverrors.KVs(kve)
+	fields := make(map[string]interface{}, len(kvs))
+	for k, v := range kvs {
+		if k == kverrors.MessageKey || k == kverrors.CauseKey {
+			continue
+		}
+		fields[k] = v
+	}
+	return fields
+}
+
+// FormatValue renders a field value. Strings are quoted if they contain
+// whitespace, quotes or '='. Maps and slices are rendered as JSON.
+func FormatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		if v == "" || strings.ContainsAny(v, " \t\r\n\"=") {
+			return strconv.Quote(v)
+		}
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
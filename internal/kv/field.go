@@ -0,0 +1,100 @@
+package kv
+
+import (
+	"strconv"
+	"time"
+)
+
+type fieldKind uint8
+
+const (
+	anyKind fieldKind = iota
+	stringKind
+	intKind
+	durationKind
+	boolKind
+	errKind
+)
+
+// Field is a typed key/value pair, see the field package. ToMap keeps fields
+// as they are so their values are never boxed and AppendJSON encodes them
+// without reflection.
+type Field struct {
+	key  string
+	kind fieldKind
+	num  int64
+	str  string
+	val  interface{}
+}
+
+// StringField creates a string field
+func StringField(key, value string) Field {
+	return Field{key: key, kind: stringKind, str: value}
+}
+
+// IntField creates an int field
+func IntField(key string, value int) Field {
+	return Field{key: key, kind: intKind, num: int64(value)}
+}
+
+// DurationField creates a time.Duration field
+func DurationField(key string, value time.Duration) Field {
+	return Field{key: key, kind: durationKind, num: int64(value)}
+}
+
+// BoolField creates a bool field
+func BoolField(key string, value bool) Field {
+	var num int64
+	if value {
+		num = 1
+	}
+	return Field{key: key, kind: boolKind, num: num}
+}
+
+// ErrField creates an error field
+func ErrField(key string, err error) Field {
+	return Field{key: key, kind: errKind, val: err}
+}
+
+// AnyField creates a field of any type
+func AnyField(key string, value interface{}) Field {
+	return Field{key: key, kind: anyKind, val: value}
+}
+
+// Key returns the key of the field
+func (f Field) Key() string {
+	return f.key
+}
+
+// Value returns the value of the field
+func (f Field) Value() interface{} {
+	switch f.kind {
+	case stringKind:
+		return f.str
+	case intKind:
+		return int(f.num)
+	case durationKind:
+		return time.Duration(f.num)
+	case boolKind:
+		return f.num == 1
+	default:
+		return f.val
+	}
+}
+
+// appendJSON appends the value of the field encoded as JSON to dst
+func (f Field) appendJSON(dst []byte) ([]byte, error) {
+	switch f.kind {
+	case stringKind:
+		return appendString(dst, f.str), nil
+	case intKind, durationKind:
+		return strconv.AppendInt(dst, f.num, 10), nil
+	case boolKind:
+		return strconv.AppendBool(dst, f.num == 1), nil
+	case errKind:
+		if err, ok := f.val.(error); ok && !isMarshaler(err) {
+			return appendString(dst, err.Error()), nil
+		}
+	}
+	return appendValue(dst, f.val)
+}
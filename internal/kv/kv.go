@@ -4,12 +4,23 @@ import (
 	"fmt"
 )
 
-// ToMap converts keysAndValues to a map
+// ToMap converts keysAndValues to a map. A Field takes a single element
+// instead of a key and a value and is stored as it is.
 func ToMap(keysAndValues ...interface{}) map[string]interface{} {
 	kvlen := len(keysAndValues)
 	kve := make(map[string]interface{}, kvlen/2)
 
-	for i, j := 0, 1; i < kvlen && j < kvlen; i, j = i+2, j+2 {
+	for i := 0; i < kvlen; {
+		if f, ok := keysAndValues[i].(Field); ok {
+			// store the element itself, storing f would box it again
+			kve[f.key] = keysAndValues[i]
+			i++
+			continue
+		}
+		if i+1 >= kvlen {
+			break
+		}
+
 		key, ok := keysAndValues[i].(string)
 
 		// Expecting a string as the key, however will make a
@@ -18,12 +29,25 @@ func ToMap(keysAndValues ...interface{}) map[string]interface{} {
 			key = fmt.Sprintf("%s", keysAndValues[i])
 		}
 
-		kve[key] = keysAndValues[j]
+		kve[key] = keysAndValues[i+1]
+		i += 2
 	}
 
 	return kve
 }
 
+// ToValueMap converts keysAndValues to a map like ToMap but stores the values
+// of fields instead of the fields
+func ToValueMap(keysAndValues ...interface{}) map[string]interface{} {
+	m := ToMap(keysAndValues...)
+	for k, v := range m {
+		if f, ok := v.(Field); ok {
+			m[k] = f.Value()
+		}
+	}
+	return m
+}
+
 // FromMap converts a map to a key/value slice
 func FromMap(m map[string]interface{}) []interface{} {
 	res := make([]interface{}, 0, len(m)*2)
@@ -0,0 +1,123 @@
+package sink
+
+import (
+	"fmt"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+// ErrorMode defines how errors are rendered in log lines
+type ErrorMode int
+
+const (
+	// NestedErrors logs the error as an object under ErrorKey with its cause
+	// nested under the cause key
+	NestedErrors ErrorMode = iota
+	// FlattenedErrors logs the error message and the key/values of the
+	// whole chain as top level fields with a prefix, see kverrors.MergeKVs.
+	// Values set by outer errors win.
+	FlattenedErrors
+	// ChainErrors logs the error as an object under ErrorKey with the error
+	// message and an array of every error of the chain under CausesKey
+	ChainErrors
+)
+
+// DefaultErrorPrefix is the prefix of error fields in FlattenedErrors mode
+const DefaultErrorPrefix = "error."
+
+// Keys used to log the error chain in ChainErrors mode
+const (
+	CausesKey = "causes"
+	ErrorsKey = "errors"
+)
+
+// errorKeysAndValues returns the key/value pairs logged for err. The Go type
+// of errors which are not a *kverrors.KVError is logged if withType is set.
+func errorKeysAndValues(err error, mode ErrorMode, prefix string, withType bool) []interface{} {
+	switch mode {
+	case FlattenedErrors:
+		kvs := kverrors.MergeKVs(err, kverrors.PreferOuter)
+		res := make([]interface{}, 0, len(kvs)*2+2)
+		res = append(res, prefix+kverrors.MessageKey, err.Error())
+		for k, kv := range kvs {
+			res = append(res, prefix+k, flatValue(kv.Value))
+		}
+		return res
+	case ChainErrors:
+		return []interface{}{ErrorKey, chainValue(err, withType)}
+	default:
+		v := kverrors.JSONValue(err, withType)
+		if text, ok := v.(string); ok {
+			v = map[string]interface{}{kverrors.MessageKey: text}
+		}
+		return []interface{}{ErrorKey, v}
+	}
+}
+
+// chainValue returns the value logged under ErrorKey for err in ChainErrors
+// mode. Every error of the chain is a link with its own message and
+// key/values. Links of errors which unwrap to multiple errors, like an
+// *Aggregate, carry them under ErrorsKey. The errors are visited like with
+// kverrors.Walk.
+func chainValue(err error, withType bool) map[string]interface{} {
+	var causes []interface{}
+	for e := err; e != nil; {
+		link := map[string]interface{}{kverrors.MessageKey: kverrors.LinkMessage(e)}
+		children := kverrors.Children(e)
+
+		switch le := e.(type) {
+		case *kverrors.KVError:
+			for k, v := range kverrors.KVs(le) {
+				if k != kverrors.MessageKey && k != kverrors.CauseKey {
+					link[k] = flatValue(v)
+				}
+			}
+		case *kverrors.Aggregate:
+			errs := make([]interface{}, 0, le.Len())
+			for _, item := range le.Items() {
+				m := chainValue(item.Err, withType)
+				for k, v := range item.KVs {
+					m[k] = flatValue(v)
+				}
+				errs = append(errs, m)
+			}
+			link[ErrorsKey] = errs
+			children = nil
+		default:
+			if len(children) > 1 {
+				errs := make([]interface{}, 0, len(children))
+				for _, child := range children {
+					errs = append(errs, chainValue(child, withType))
+				}
+				link[ErrorsKey] = errs
+			}
+		}
+
+		switch e.(type) {
+		case *kverrors.KVError, *kverrors.Aggregate:
+		default:
+			if withType {
+				link[kverrors.ErrorTypeKey] = fmt.Sprintf("%T", e)
+			}
+		}
+		causes = append(causes, link)
+
+		e = nil
+		if len(children) == 1 {
+			e = children[0]
+		}
+	}
+
+	return map[string]interface{}{
+		kverrors.MessageKey: err.Error(),
+		CausesKey:           causes,
+	}
+}
+
+// flatValue returns v or the message of v if it is an error
+func flatValue(v interface{}) interface{} {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	return v
+}
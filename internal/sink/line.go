@@ -4,18 +4,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+
+	"github.com/ViaQ/logerr/v2/internal/kv"
 )
 
 // Keys used to log specific builtin fields
 const (
-	TimeStampKey = "_ts"
-	FileLineKey  = "_file:line"
-	LevelKey     = "_level"
-	ComponentKey = "_component"
-	MessageKey   = "_message"
-	ErrorKey     = "_error"
+	TimeStampKey   = "_ts"
+	FileLineKey    = "_file:line"
+	LevelKey       = "_level"
+	ComponentKey   = "_component"
+	MessageKey     = "_message"
+	ErrorKey       = "_error"
+	ErrorCodeKey   = "_error_code"
+	ErrorClassKey  = "_error_class"
+	RetryAfterKey  = "_retry_after"
+	FingerprintKey = "_error_fingerprint"
 )
 
+// StackTraceKey is the key of the stack trace of a logged error, see
+// kverrors.Stack
+const StackTraceKey = "stacktrace"
+
 // Line orders log line fields
 type Line struct {
 	Timestamp string
@@ -64,7 +74,7 @@ func (l Line) MarshalJSON() ([]byte, error) {
 	}
 	lineValue = lineValue[1 : len(lineValue)-1]
 
-	contextValue, err := json.Marshal(lineTemp.Context)
+	contextValue, err := kv.AppendJSON(nil, lineTemp.Context)
 	if err != nil {
 		return nil, err
 	}
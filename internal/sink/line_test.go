@@ -1,9 +1,15 @@
 package sink_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"testing"
+	"time"
 
+	"github.com/ViaQ/logerr/v2/field"
+	"github.com/ViaQ/logerr/v2/internal/kv"
 	"github.com/ViaQ/logerr/v2/internal/sink"
 	"github.com/ViaQ/logerr/v2/kverrors"
 	"github.com/stretchr/testify/require"
@@ -94,3 +100,45 @@ func TestLine_WithNestedKVError(t *testing.T) {
 	require.Contains(t, logMsg, msgValue)
 	require.Contains(t, logMsg, errorValue)
 }
+
+func TestLine_ContextEncoding(t *testing.T) {
+	ctx := map[string]interface{}{
+		"string":   "hello, world",
+		"escaped":  "<a href=\"x\">\n\tü &  </a>",
+		"int":      -42,
+		"int64":    int64(math.MaxInt64),
+		"uint8":    uint8(255),
+		"bool":     true,
+		"float":    1.5e-7,
+		"duration": 1500 * time.Millisecond,
+		"nil":      nil,
+		"slice":    []string{"a", "b"},
+		"map":      map[string]int{"a": 1},
+		"time":     time.Date(2022, 5, 1, 10, 11, 12, 0, time.UTC),
+		"kverror":  kverrors.New("an error", "key", "value"),
+	}
+
+	b, err := json.Marshal(sink.Line{Context: ctx})
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(ctx)
+	require.NoError(t, err)
+	require.Contains(t, string(b), string(expected[1:len(expected)-1]))
+}
+
+func TestLine_ContextEncodingWithFields(t *testing.T) {
+	ctx := kv.ToMap(
+		field.String("string", "<b>"),
+		field.Int("int", -42),
+		field.Duration("duration", 1500*time.Millisecond),
+		field.Bool("bool", true),
+		field.Err("err", io.EOF),
+		field.Err("kverror", kverrors.New("an error", "key", "value")),
+		field.Any("slice", []string{"a"}),
+		"plain_err", io.EOF,
+	)
+
+	b, err := json.Marshal(sink.Line{Context: ctx})
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"bool":true,"duration":1500000000,"err":"EOF","int":-42,"kverror":{"key":"value","msg":"an error"},"plain_err":{},"slice":["a"],"string":"\u003cb\u003e"`)
+}
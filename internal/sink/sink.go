@@ -1,6 +1,7 @@
 package sink
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -25,22 +26,28 @@ var TimestampFunc = func() string {
 
 // Sink writes logs to a specified output
 type Sink struct {
-	mtx       sync.RWMutex
-	verbosity Verbosity
-	output    io.Writer
-	context   map[string]interface{}
-	encoder   Encoder
-	name      string
+	mtx         sync.RWMutex
+	verbosity   Verbosity
+	output      io.Writer
+	context     map[string]interface{}
+	encoder     Encoder
+	name        string
+	extractor   TraceExtractor
+	errorTypes  bool
+	errorMode   ErrorMode
+	errorPrefix string
 }
 
 // NewLogSink creates a new logsink
 func NewLogSink(name string, w io.Writer, v Verbosity, e Encoder, keysAndValues ...interface{}) *Sink {
 	return &Sink{
-		name:      name,
-		verbosity: v,
-		output:    w,
-		context:   kv.ToMap(keysAndValues...),
-		encoder:   e,
+		name:        name,
+		verbosity:   v,
+		output:      w,
+		context:     kv.ToMap(keysAndValues...),
+		encoder:     e,
+		extractor:   TraceParentExtractor{},
+		errorPrefix: DefaultErrorPrefix,
 	}
 }
 
@@ -62,30 +69,59 @@ func (s *Sink) Enabled(level int) bool {
 // Info logs a non-error message with the given key/value pairs as context. Info
 // will check to see if the log is enabled for the logger's level before recording.
 func (s *Sink) Info(level int, msg string, keysAndValues ...interface{}) {
-	if !s.Enabled(level) {
-		return
+	if l, ok := s.InfoLine(level, msg, keysAndValues...); ok {
+		s.write(l)
 	}
-	s.log(msg, combine(s.context, keysAndValues...))
 }
 
 // Error logs an error, with the given message and key/value pairs as context. Unlike
 // Info, it bypasses the Enabled check. Logs will always be recorded from this method.
 func (s *Sink) Error(err error, msg string, keysAndValues ...interface{}) {
-	// Use 0 as the level since it is the smallest level a logger can have
-	// and thus will always pass the Enabled check.
+	s.write(s.ErrorLine(err, msg, keysAndValues...))
+}
+
+// InfoLine returns the line written by Info and whether it is enabled for the
+// level. This allows other LogSinks to use the formatting of the logsink. The
+// file:line of the line is the caller of the method calling InfoLine.
+func (s *Sink) InfoLine(level int, msg string, keysAndValues ...interface{}) (Line, bool) {
+	if !s.Enabled(level) {
+		return Line{}, false
+	}
+	return s.line(msg, combine(s.context, keysAndValues...)), true
+}
+
+// ErrorLine returns the line written by Error, see InfoLine
+func (s *Sink) ErrorLine(err error, msg string, keysAndValues ...interface{}) Line {
+	// Call line directly to keep the caller depth the same as for InfoLine.
+	return s.line(msg, s.errorContext(err, keysAndValues))
+}
+
+// errorContext returns the context of a line logging err
+func (s *Sink) errorContext(err error, keysAndValues []interface{}) map[string]interface{} {
 	if err == nil {
-		s.Info(0, msg, keysAndValues)
-		return
+		return combine(s.context, keysAndValues...)
 	}
 
-	switch err.(type) {
-	case *kverrors.KVError:
-		// nothing to be done
-	default:
-		err = kverrors.New(err.Error())
+	keysAndValues = append(keysAndValues, FingerprintKey, kverrors.Fingerprint(err))
+	if code := kverrors.Code(err); code != "" {
+		keysAndValues = append(keysAndValues, ErrorCodeKey, code)
+	}
+	if classes := kverrors.Classes(err); len(classes) > 0 {
+		keysAndValues = append(keysAndValues, ErrorClassKey, classes)
+	}
+	if after, ok := kverrors.RetryAfter(err); ok {
+		keysAndValues = append(keysAndValues, RetryAfterKey, after.String())
 	}
+	if stack := kverrors.Stack(err); stack != nil {
+		keysAndValues = append(keysAndValues, StackTraceKey, stack.String())
+	}
+
+	s.mtx.RLock()
+	mode, prefix, errorTypes := s.errorMode, s.errorPrefix, s.errorTypes
+	s.mtx.RUnlock()
 
-	s.Info(0, msg, append(keysAndValues, ErrorKey, err)...)
+	keysAndValues = append(keysAndValues, errorKeysAndValues(err, mode, prefix, errorTypes)...)
+	return combine(s.context, keysAndValues...)
 }
 
 // WithValues clones the logsink and appends keysAndValues.
@@ -95,10 +131,32 @@ func (s *Sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
 
 	ss := NewLogSink(s.name, s.output, s.verbosity, s.encoder)
 	ss.context = combine(s.context, keysAndValues...)
+	ss.extractor = s.extractor
+	ss.errorTypes = s.errorTypes
+	ss.errorMode = s.errorMode
+	ss.errorPrefix = s.errorPrefix
 
 	return ss
 }
 
+// WithContext clones the logsink and appends the trace correlation fields
+// found in ctx by the TraceExtractor. The logsink is returned unchanged if
+// ctx carries no trace context.
+func (s *Sink) WithContext(ctx context.Context) logr.LogSink {
+	s.mtx.RLock()
+	extractor := s.extractor
+	s.mtx.RUnlock()
+
+	if extractor == nil {
+		return s
+	}
+	tc, ok := extractor.Extract(ctx)
+	if !ok {
+		return s
+	}
+	return s.WithValues(tc.keysAndValues()...)
+}
+
 // WithName clones the logsink and overwrites the name.
 func (s *Sink) WithName(name string) logr.LogSink {
 	s.mtx.Lock()
@@ -109,7 +167,14 @@ func (s *Sink) WithName(name string) logr.LogSink {
 		newName = fmt.Sprintf("%s_%s", s.name, name)
 	}
 
-	return NewLogSink(newName, s.output, s.verbosity, s.encoder, s.context)
+	ss := NewLogSink(newName, s.output, s.verbosity, s.encoder)
+	ss.context = s.context
+	ss.extractor = s.extractor
+	ss.errorTypes = s.errorTypes
+	ss.errorMode = s.errorMode
+	ss.errorPrefix = s.errorPrefix
+
+	return ss
 }
 
 // SetOutput sets the writer that JSON is written to
@@ -120,6 +185,40 @@ func (s *Sink) SetOutput(w io.Writer) {
 	s.output = w
 }
 
+// SetTraceExtractor sets the TraceExtractor used by WithContext. A nil
+// extractor disables trace correlation.
+func (s *Sink) SetTraceExtractor(e TraceExtractor) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.extractor = e
+}
+
+// SetErrorTypes sets whether the Go type of errors which are not a
+// *kverrors.KVError is logged with their message
+func (s *Sink) SetErrorTypes(enabled bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.errorTypes = enabled
+}
+
+// SetErrorMode sets how errors are rendered in log lines
+func (s *Sink) SetErrorMode(mode ErrorMode) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.errorMode = mode
+}
+
+// SetErrorPrefix sets the prefix of error fields in FlattenedErrors mode
+func (s *Sink) SetErrorPrefix(prefix string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.errorPrefix = prefix
+}
+
 // SetVerbosity sets the log level allowed by the logsink
 func (s *Sink) SetVerbosity(v int) {
 	s.mtx.Lock()
@@ -133,13 +232,13 @@ func (s *Sink) GetVerbosity() int {
 	return int(s.verbosity)
 }
 
-// log will log the message. It DOES NOT check Enabled() first so that should
-// be checked by it's callers
-func (s *Sink) log(msg string, context map[string]interface{}) {
-	_, file, line, _ := runtime.Caller(3)
+// line creates the line for the message. It DOES NOT check Enabled() first so
+// that should be checked by it's callers
+func (s *Sink) line(msg string, context map[string]interface{}) Line {
+	_, file, line, _ := runtime.Caller(4)
 	file = sourcePath(file)
 
-	m := Line{
+	return Line{
 		Timestamp: TimestampFunc(),
 		FileLine:  fmt.Sprintf("%s:%s", file, strconv.Itoa(line)),
 		Verbosity: s.verbosity.String(),
@@ -147,7 +246,10 @@ func (s *Sink) log(msg string, context map[string]interface{}) {
 		Message:   msg,
 		Context:   context,
 	}
+}
 
+// write encodes the line to the output
+func (s *Sink) write(m Line) {
 	err := s.encoder.Encode(s.output, m)
 	if err != nil {
 		// expand first so we can quote later
@@ -2,10 +2,12 @@ package sink_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/ViaQ/logerr/v2/internal/sink"
 	"github.com/ViaQ/logerr/v2/kverrors"
@@ -173,6 +175,13 @@ func TestSink_WithName(t *testing.T) {
 	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:%q`, sink.ComponentKey, "new_append"))
 }
 
+func TestSink_WithName_KeepsValues(t *testing.T) {
+	s, b := sinkWithBuffer("new", 0, "hello", "world")
+
+	s.WithName("append").Info(0, "First.")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:%q`, "hello", "world"))
+}
+
 func TestSink_WithEmptyName(t *testing.T) {
 	s, b := sinkWithBuffer("", 0)
 
@@ -242,3 +251,163 @@ func sinkWithBuffer(component string, level int, keyValuePairs ...interface{}) (
 
 	return sink, buffer
 }
+
+func TestSink_Error_WithStackTrace(t *testing.T) {
+	s, b := sinkWithBuffer("", 0)
+
+	s.Error(kverrors.NewWithStack("an error"), "hello, world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:"github.com/ViaQ/logerr/v2/internal/sink_test.TestSink_Error_WithStackTrace`, sink.StackTraceKey))
+
+	b.Reset()
+	s.Error(kverrors.New("an error"), "hello, world")
+	require.NotContains(t, string(b.Bytes()), sink.StackTraceKey)
+}
+
+func TestSink_Error_WithCode(t *testing.T) {
+	errNotFound := kverrors.Define("NotFound", "resource not found")
+	s, b := sinkWithBuffer("", 0)
+
+	s.Error(fmt.Errorf("lookup failed: %w", errNotFound.New("name", "foo")), "hello, world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:%q`, sink.ErrorCodeKey, "NotFound"))
+
+	b.Reset()
+	s.Error(kverrors.New("an error"), "hello, world")
+	require.NotContains(t, string(b.Bytes()), sink.ErrorCodeKey)
+}
+
+func TestSink_Error_WithAggregate(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("is required"), "field", "spec.name")
+	errs.Add(kverrors.New("must not be negative"), "field", "spec.replicas")
+
+	s, b := sinkWithBuffer("", 0)
+	s.Error(&errs, "validation failed")
+
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:[{"field":"spec.name","msg":"is required"},{"field":"spec.replicas","msg":"must not be negative"}]`, sink.ErrorKey))
+}
+
+func TestSink_Error_WithWrappedKVError(t *testing.T) {
+	err := fmt.Errorf("sync failed: %w", kverrors.Wrap(io.EOF, "failed to read", "file", "a.json"))
+
+	s, b := sinkWithBuffer("", 0)
+	s.Error(err, "hello, world")
+
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:{"cause":{"cause":"EOF","file":"a.json","msg":"failed to read"},"msg":"sync failed"}`, sink.ErrorKey))
+}
+
+func TestSink_Error_WithErrorTypes(t *testing.T) {
+	err := kverrors.Wrap(fmt.Errorf("read: %w", io.EOF), "failed to load")
+
+	s, b := sinkWithBuffer("", 0)
+	s.SetErrorTypes(true)
+	s.WithValues("hello", "world").Error(err, "hello, world")
+
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:{"cause":{"error_type":"*fmt.wrapError","msg":"read: EOF"},"msg":"failed to load"}`, sink.ErrorKey))
+
+	// the option is per sink and independent of the global switch
+	b.Reset()
+	s.SetErrorTypes(false)
+	kverrors.EnableErrorTypes(true)
+	defer kverrors.EnableErrorTypes(false)
+	s.Error(err, "hello, world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:{"cause":"read: EOF","msg":"failed to load"}`, sink.ErrorKey))
+}
+
+func TestSink_Error_WithNilErrorKeepsValues(t *testing.T) {
+	s, b := sinkWithBuffer("", 0)
+
+	s.Error(nil, "hello, world", "hello", "world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:%q`, "hello", "world"))
+}
+
+func TestSink_Error_FileLine(t *testing.T) {
+	s, b := sinkWithBuffer("", 2)
+
+	logr.New(s).Error(io.EOF, "hello, world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:"sink_test.go:`, sink.FileLineKey))
+}
+
+func TestSink_Error_FlattenedErrors(t *testing.T) {
+	err := kverrors.Wrap(kverrors.New("connection refused", "host", "db", "user", "inner"), "failed to query", "user", "alice")
+
+	s, b := sinkWithBuffer("", 0)
+	s.SetErrorMode(sink.FlattenedErrors)
+	s.WithValues("hello", "world").Error(err, "hello, world")
+
+	logMsg := string(b.Bytes())
+	require.Contains(t, logMsg, `"error.msg":"failed to query: connection refused"`)
+	require.Contains(t, logMsg, `"error.host":"db"`)
+	require.Contains(t, logMsg, `"error.user":"alice"`)
+	require.NotContains(t, logMsg, fmt.Sprintf("%q:", sink.ErrorKey))
+
+	b.Reset()
+	s.SetErrorPrefix("err_")
+	s.Error(err, "hello, world")
+	require.Contains(t, string(b.Bytes()), `"err_host":"db"`)
+}
+
+func TestSink_Error_ChainErrors(t *testing.T) {
+	err := kverrors.Wrap(fmt.Errorf("query: %w", kverrors.Wrap(io.EOF, "failed to read", "host", "db")), "failed to load", "user", "alice")
+
+	s, b := sinkWithBuffer("", 0)
+	s.SetErrorMode(sink.ChainErrors)
+	s.Error(err, "hello, world")
+
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:{"causes":[{"msg":"failed to load","user":"alice"},{"msg":"query"},{"host":"db","msg":"failed to read"},{"msg":"EOF"}],"msg":"failed to load: query: failed to read: EOF"}`, sink.ErrorKey))
+}
+
+func TestSink_Error_ChainErrorsWithAggregate(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("is required"), "field", "spec.name")
+	errs.Add(io.EOF)
+
+	s, b := sinkWithBuffer("", 0)
+	s.SetErrorMode(sink.ChainErrors)
+	s.SetErrorTypes(true)
+	s.Error(kverrors.Wrap(&errs, "invalid spec"), "hello, world")
+
+	require.Contains(t, string(b.Bytes()), `{"errors":[{"causes":[{"msg":"is required"}],"field":"spec.name","msg":"is required"},{"causes":[{"error_type":"*errors.errorString","msg":"EOF"}],"msg":"EOF"}],"msg":"2 errors occurred: is required (field=spec.name); EOF"}`)
+}
+
+func TestSink_Error_WithClasses(t *testing.T) {
+	s, b := sinkWithBuffer("", 0)
+
+	s.Error(kverrors.NotFound(kverrors.RateLimited(kverrors.New("an error"), 90*time.Second)), "hello, world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:["not_found","rate_limited"]`, sink.ErrorClassKey))
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:"1m30s"`, sink.RetryAfterKey))
+
+	b.Reset()
+	s.Error(kverrors.New("an error"), "hello, world")
+	require.NotContains(t, string(b.Bytes()), sink.ErrorClassKey)
+	require.NotContains(t, string(b.Bytes()), sink.RetryAfterKey)
+}
+
+func TestSink_Error_WithTree(t *testing.T) {
+	err := kverrors.Wrap(errors.Join(kverrors.New("a", "key", "a"), io.EOF), "outer")
+
+	s, b := sinkWithBuffer("", 0)
+	s.Error(err, "hello, world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:{"cause":{"cause":[{"key":"a","msg":"a"},"EOF"],"msg":"a\nEOF"},"msg":"outer"}`, sink.ErrorKey))
+
+	b.Reset()
+	s.SetErrorMode(sink.ChainErrors)
+	s.Error(err, "hello, world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:{"causes":[{"msg":"outer"},{"errors":[{"causes":[{"key":"a","msg":"a"}],"msg":"a"},{"causes":[{"msg":"EOF"}],"msg":"EOF"}],"msg":"a\nEOF"}],"msg":"outer: a\nEOF"}`, sink.ErrorKey))
+
+	b.Reset()
+	s.SetErrorMode(sink.FlattenedErrors)
+	s.Error(err, "hello, world")
+	require.Contains(t, string(b.Bytes()), `"error.key":"a"`)
+
+	b.Reset()
+	s.Error(errors.Join(kverrors.Wrap(kverrors.New("deep", "key", "deep"), "mid"), kverrors.New("shallow", "key", "shallow")), "hello, world")
+	require.Contains(t, string(b.Bytes()), `"error.key":"shallow"`)
+}
+
+func TestSink_Error_WithFingerprint(t *testing.T) {
+	s, b := sinkWithBuffer("", 0)
+
+	err := kverrors.New("an error", "key", "value")
+	s.Error(err, "hello, world")
+	require.Contains(t, string(b.Bytes()), fmt.Sprintf(`%q:%q`, sink.FingerprintKey, kverrors.Fingerprint(err)))
+}
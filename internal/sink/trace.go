@@ -0,0 +1,116 @@
+package sink
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+// Keys used to log trace correlation fields
+const (
+	TraceIDKey      = "trace_id"
+	SpanIDKey       = "span_id"
+	TraceSampledKey = "trace_sampled"
+)
+
+// TraceContext identifies the trace and span a log line belongs to
+type TraceContext struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+// TraceExtractor extracts the TraceContext carried by a context.Context.
+// Implementations can adapt any tracing provider, e.g. by reading the span
+// context of an OpenTelemetry span stored in ctx.
+type TraceExtractor interface {
+	Extract(ctx context.Context) (TraceContext, bool)
+}
+
+// TraceExtractorFunc adapts a function to a TraceExtractor
+type TraceExtractorFunc func(ctx context.Context) (TraceContext, bool)
+
+// Extract calls f(ctx)
+func (f TraceExtractorFunc) Extract(ctx context.Context) (TraceContext, bool) {
+	return f(ctx)
+}
+
+type traceParentKey struct{}
+
+// ContextWithTraceParent returns a copy of ctx which carries the value of a
+// W3C traceparent header
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, traceParent)
+}
+
+// TraceParentExtractor extracts the TraceContext from a W3C traceparent value
+// stored with ContextWithTraceParent
+type TraceParentExtractor struct{}
+
+// Extract implements TraceExtractor
+func (TraceParentExtractor) Extract(ctx context.Context) (TraceContext, bool) {
+	tp, ok := ctx.Value(traceParentKey{}).(string)
+	if !ok {
+		return TraceContext{}, false
+	}
+	tc, err := ParseTraceParent(tp)
+	if err != nil {
+		return TraceContext{}, false
+	}
+	return tc, true
+}
+
+// ParseTraceParent parses the value of a W3C traceparent header, for example
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func ParseTraceParent(traceParent string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, kverrors.New("invalid traceparent", "traceparent", traceParent)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+
+	switch {
+	case !isHex(version, 2) || version == "ff", version == "00" && len(parts) != 4:
+		return TraceContext{}, kverrors.New("invalid traceparent version", "traceparent", traceParent)
+	case !isHex(traceID, 32) || isZero(traceID):
+		return TraceContext{}, kverrors.New("invalid trace id", "traceparent", traceParent)
+	case !isHex(spanID, 16) || isZero(spanID):
+		return TraceContext{}, kverrors.New("invalid span id", "traceparent", traceParent)
+	case !isHex(flags, 2):
+		return TraceContext{}, kverrors.New("invalid trace flags", "traceparent", traceParent)
+	}
+
+	f, _ := hex.DecodeString(flags)
+	return TraceContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: f[0]&0x01 == 0x01,
+	}, nil
+}
+
+// keysAndValues returns the trace correlation fields as key/value pairs
+func (tc TraceContext) keysAndValues() []interface{} {
+	return []interface{}{
+		TraceIDKey, tc.TraceID,
+		SpanIDKey, tc.SpanID,
+		TraceSampledKey, tc.Sampled,
+	}
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,76 @@
+package sink_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/stretchr/testify/require"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tc, err := sink.ParseTraceParent(traceParent)
+	require.NoError(t, err)
+	require.Equal(t, sink.TraceContext{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+		Sampled: true,
+	}, tc)
+
+	tc, err = sink.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	require.NoError(t, err)
+	require.False(t, tc.Sampled)
+}
+
+func TestParseTraceParent_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0x",
+	}
+
+	for _, tp := range invalid {
+		_, err := sink.ParseTraceParent(tp)
+		require.Error(t, err, tp)
+	}
+}
+
+func TestSink_WithContext_AddsTraceFields(t *testing.T) {
+	s, b := sinkWithBuffer("", 0)
+	ctx := sink.ContextWithTraceParent(context.Background(), traceParent)
+
+	s.WithContext(ctx).Info(0, "hello, world")
+
+	logMsg := b.String()
+	require.Contains(t, logMsg, fmt.Sprintf(`%q:%q`, sink.TraceIDKey, "4bf92f3577b34da6a3ce929d0e0e4736"))
+	require.Contains(t, logMsg, fmt.Sprintf(`%q:%q`, sink.SpanIDKey, "00f067aa0ba902b7"))
+	require.Contains(t, logMsg, fmt.Sprintf(`%q:true`, sink.TraceSampledKey))
+}
+
+func TestSink_WithContext_WithoutTraceContext(t *testing.T) {
+	s, b := sinkWithBuffer("", 0)
+
+	s.WithContext(context.Background()).Info(0, "hello, world")
+
+	require.NotContains(t, b.String(), sink.TraceIDKey)
+}
+
+func TestSink_SetTraceExtractor(t *testing.T) {
+	s, b := sinkWithBuffer("", 0)
+	s.SetTraceExtractor(sink.TraceExtractorFunc(func(context.Context) (sink.TraceContext, bool) {
+		return sink.TraceContext{TraceID: "trace", SpanID: "span"}, true
+	}))
+
+	s.WithName("child").(*sink.Sink).WithContext(context.Background()).Info(0, "hello, world")
+
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:%q`, sink.TraceIDKey, "trace"))
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:false`, sink.TraceSampledKey))
+}
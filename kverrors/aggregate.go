@@ -0,0 +1,136 @@
+package kverrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/internal/kv"
+)
+
+// Aggregate collects multiple errors, each with its own key/values such as
+// the path of an invalid field. The zero value is an empty aggregate ready
+// to use. An Aggregate is not safe for concurrent use.
+//
+// Example:
+//
+//	var errs kverrors.Aggregate
+//	if spec.Replicas < 0 {
+//	    errs.Add(kverrors.New("must not be negative"), "field", "spec.replicas")
+//	}
+//	if spec.Name == "" {
+//	    errs.Add(kverrors.New("is required"), "field", "spec.name")
+//	}
+//	return errs.ErrorOrNil()
+type Aggregate struct {
+	items []AggregateItem
+}
+
+// AggregateItem is an error of an Aggregate with its key/values
+type AggregateItem struct {
+	Err error
+	KVs map[string]interface{}
+}
+
+// Add adds err with keys and values to the aggregate. Nil errors are
+// ignored.
+func (a *Aggregate) Add(err error, keysAndValues ...interface{}) {
+	if err == nil {
+		return
+	}
+	a.items = append(a.items, AggregateItem{Err: err, KVs: kv.ToValueMap(keysAndValues...)})
+}
+
+// Len returns the number of errors in the aggregate
+func (a *Aggregate) Len() int {
+	return len(a.items)
+}
+
+// Items returns the errors of the aggregate with their key/values
+func (a *Aggregate) Items() []AggregateItem {
+	return append([]AggregateItem(nil), a.items...)
+}
+
+// ErrorOrNil returns the aggregate as an error or nil if it is empty
+func (a *Aggregate) ErrorOrNil() error {
+	if a == nil || len(a.items) == 0 {
+		return nil
+	}
+	return a
+}
+
+// Unwrap returns the errors of the aggregate. This is required to work with
+// the standard library errors.Is and errors.As
+func (a *Aggregate) Unwrap() []error {
+	errs := make([]error, 0, len(a.items))
+	for _, item := range a.items {
+		errs = append(errs, item.Err)
+	}
+	return errs
+}
+
+// Error returns all errors with their key/values on a single line, for
+// example:
+//
+//	2 errors occurred: must not be negative (field=spec.replicas); is required (field=spec.name)
+func (a *Aggregate) Error() string {
+	msgs := make([]string, 0, len(a.items))
+	for _, item := range a.items {
+		msgs = append(msgs, item.String())
+	}
+
+	switch len(msgs) {
+	case 0:
+		return "no errors occurred"
+	case 1:
+		return msgs[0]
+	default:
+		return fmt.Sprintf("%d errors occurred: %s", len(msgs), strings.Join(msgs, "; "))
+	}
+}
+
+// String returns the error text followed by the sorted key/values
+func (i AggregateItem) String() string {
+	if len(i.KVs) == 0 {
+		return i.Err.Error()
+	}
+
+	keys := make([]string, 0, len(i.KVs))
+	for k := range i.KVs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, i.KVs[k]))
+	}
+	return fmt.Sprintf("%s (%s)", i.Err.Error(), strings.Join(pairs, ", "))
+}
+
+// MarshalJSON implements json.Marshaler. The aggregate is marshaled as an
+// array with one object per error containing the key/values of the item and
+// the marshaled error. Errors which are not a *KVError are marshaled as their
+// message.
+func (a *Aggregate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.jsonItems(ErrorTypesEnabled()))
+}
+
+// jsonItems returns the JSON representation of the items of the aggregate,
+// see JSONValue
+func (a *Aggregate) jsonItems(withTypes bool) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(a.items))
+	for _, item := range a.items {
+		m := jsonMap(item.KVs, withTypes)
+		if v, ok := JSONValue(item.Err, withTypes).(map[string]interface{}); ok {
+			for k, iv := range v {
+				m[k] = iv
+			}
+		} else {
+			m[MessageKey] = item.Err.Error()
+		}
+		items = append(items, m)
+	}
+	return items
+}
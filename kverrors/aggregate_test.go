@@ -0,0 +1,68 @@
+package kverrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate_ErrorOrNil(t *testing.T) {
+	var errs kverrors.Aggregate
+	require.Nil(t, errs.ErrorOrNil())
+
+	errs.Add(nil, "field", "spec.name")
+	require.Nil(t, errs.ErrorOrNil())
+	require.Equal(t, 0, errs.Len())
+
+	errs.Add(io.EOF)
+	require.Equal(t, &errs, errs.ErrorOrNil())
+	require.Equal(t, 1, errs.Len())
+}
+
+func TestAggregate_Error(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("must not be negative", "value", -1), "field", "spec.replicas")
+	require.Equal(t, "must not be negative (field=spec.replicas)", errs.Error())
+
+	errs.Add(io.ErrUnexpectedEOF, "field", "spec.name", "index", 2)
+	errs.Add(io.EOF)
+	require.Equal(t, "3 errors occurred: must not be negative (field=spec.replicas); unexpected EOF (field=spec.name, index=2); EOF", errs.Error())
+}
+
+func TestAggregate_Unwrap(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.Wrap(io.ErrUnexpectedEOF, "failed to read"), "field", "spec.name")
+	errs.Add(&MyError{"a"}, "field", "spec.replicas")
+
+	require.Len(t, errs.Unwrap(), 2)
+	require.True(t, errors.Is(&errs, io.ErrUnexpectedEOF))
+
+	var expected *MyError
+	require.True(t, errors.As(&errs, &expected))
+	require.Equal(t, "a", expected.Letter)
+}
+
+func TestAggregate_Items(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(io.EOF, "field", "spec.name")
+
+	items := errs.Items()
+	require.Equal(t, []kverrors.AggregateItem{{Err: io.EOF, KVs: map[string]interface{}{"field": "spec.name"}}}, items)
+}
+
+func TestAggregate_MarshalJSON(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("must not be negative", "value", -1), "field", "spec.replicas")
+	errs.Add(io.EOF, "field", "spec.name")
+
+	b, err := json.Marshal(&errs)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"msg": "must not be negative", "value": -1, "field": "spec.replicas"},
+		{"msg": "EOF", "field": "spec.name"}
+	]`, string(b))
+}
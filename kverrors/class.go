@@ -0,0 +1,197 @@
+package kverrors
+
+import (
+	"time"
+)
+
+// Class classifies an error to decide how to handle it, for example whether
+// to retry the operation which failed
+type Class string
+
+// Classes of errors
+const (
+	// ClassRetryable marks errors of operations which can be retried
+	ClassRetryable Class = "retryable"
+	// ClassPermanent marks errors of operations which must not be retried
+	ClassPermanent Class = "permanent"
+	// ClassRateLimited marks errors of operations which can be retried after
+	// a delay, see RetryAfter
+	ClassRateLimited Class = "rate_limited"
+	// ClassNotFound marks errors caused by a missing resource
+	ClassNotFound Class = "not_found"
+	// ClassConflict marks errors caused by a conflicting update
+	ClassConflict Class = "conflict"
+)
+
+// Retryable marks err as the error of an operation which can be retried
+func Retryable(err error) error {
+	return classify(err, ClassRetryable, 0)
+}
+
+// Permanent marks err as the error of an operation which must not be retried
+func Permanent(err error) error {
+	return classify(err, ClassPermanent, 0)
+}
+
+// RateLimited marks err as the error of an operation which can be retried
+// after a delay
+func RateLimited(err error, after time.Duration) error {
+	return classify(err, ClassRateLimited, after)
+}
+
+// NotFound marks err as caused by a missing resource
+func NotFound(err error) error {
+	return classify(err, ClassNotFound, 0)
+}
+
+// Conflict marks err as caused by a conflicting update
+func Conflict(err error) error {
+	return classify(err, ClassConflict, 0)
+}
+
+type classMark struct {
+	class Class
+	after time.Duration
+}
+
+// classify returns a copy of err marked with class like With
+func classify(err error, class Class, after time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *KVError
+	if parent, ok := err.(*KVError); ok {
+		e = annotate(parent, nil)
+	} else {
+		e = annotateForeign(err, nil)
+	}
+	e.classes = append([]classMark{{class: class, after: after}}, e.classes...)
+	return e
+}
+
+// Classes returns the distinct classes of the errors of the chain of err,
+// outermost first. Errors with a Timeout() or Temporary() method reporting
+// true, like context.DeadlineExceeded or timeouts of the net package, are
+// classified as ClassRetryable.
+func Classes(err error) []Class {
+	var res []Class
+	visitClasses(err, func(c Class, _ time.Duration) bool {
+		for _, existing := range res {
+			if existing == c {
+				return true
+			}
+		}
+		res = append(res, c)
+		return true
+	})
+	return res
+}
+
+// IsRetryable reports whether the operation which failed with err can be
+// retried. The outermost error of the chain classified as retryable,
+// rate limited or permanent decides.
+func IsRetryable(err error) bool {
+	c, _ := retryClass(err)
+	return c == ClassRetryable || c == ClassRateLimited
+}
+
+// IsPermanent reports whether the operation which failed with err must not
+// be retried. The outermost error of the chain classified as retryable,
+// rate limited or permanent decides.
+func IsPermanent(err error) bool {
+	c, _ := retryClass(err)
+	return c == ClassPermanent
+}
+
+// RetryAfter returns the delay after which the operation which failed with
+// err can be retried if it was rate limited
+func RetryAfter(err error) (time.Duration, bool) {
+	c, after := retryClass(err)
+	return after, c == ClassRateLimited
+}
+
+// IsNotFound reports whether an error of the chain of err is classified as
+// ClassNotFound
+func IsNotFound(err error) bool {
+	return hasClass(err, ClassNotFound)
+}
+
+// IsConflict reports whether an error of the chain of err is classified as
+// ClassConflict
+func IsConflict(err error) bool {
+	return hasClass(err, ClassConflict)
+}
+
+func retryClass(err error) (Class, time.Duration) {
+	var (
+		res   Class
+		after time.Duration
+	)
+	visitClasses(err, func(c Class, a time.Duration) bool {
+		switch c {
+		case ClassRetryable, ClassRateLimited, ClassPermanent:
+			res, after = c, a
+			return false
+		}
+		return true
+	})
+	return res, after
+}
+
+func hasClass(err error, class Class) bool {
+	found := false
+	visitClasses(err, func(c Class, _ time.Duration) bool {
+		found = c == class
+		return !found
+	})
+	return found
+}
+
+// visitClasses calls fn with the class of every classified error of the chain
+// of err, outermost first, until fn returns false
+func visitClasses(err error, fn func(c Class, after time.Duration) bool) {
+	stopped := false
+	var visit func(n Node) error
+	visit = func(n Node) error {
+		kve, ok := n.Err.(*KVError)
+		if !ok {
+			if isTransient(n.Err) && !fn(ClassRetryable, 0) {
+				stopped = true
+			}
+		} else {
+			for _, m := range kve.classes {
+				if !fn(m.class, m.after) {
+					stopped = true
+					break
+				}
+			}
+			// the classes of an annotated *KVError are copied and the
+			// errors other errors unwrap to are part of the chain, only
+			// the annotated error itself needs to be visited
+			if _, ok := kve.base.(*KVError); !stopped && !ok && kve.base != nil {
+				if isTransient(kve.base) && !fn(ClassRetryable, 0) {
+					stopped = true
+				}
+			}
+		}
+
+		if stopped {
+			return SkipAll
+		}
+		return nil
+	}
+	_ = Walk(err, visit)
+}
+
+// isTransient reports whether err is a timeout or temporary error of the
+// standard library
+func isTransient(err error) bool {
+	if t, ok := err.(interface{ Timeout() bool }); ok && t.Timeout() {
+		return true
+	}
+	if t, ok := err.(interface{ Temporary() bool }); ok && t.Temporary() {
+		return true
+	}
+	return false
+}
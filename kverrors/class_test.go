@@ -0,0 +1,102 @@
+package kverrors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryable(t *testing.T) {
+	err := kverrors.Wrap(kverrors.Retryable(kverrors.New("connection refused", "host", "db")), "failed to query")
+
+	require.True(t, kverrors.IsRetryable(err))
+	require.False(t, kverrors.IsPermanent(err))
+	require.Equal(t, []kverrors.Class{kverrors.ClassRetryable}, kverrors.Classes(err))
+	require.Equal(t, "failed to query: connection refused", err.Error())
+}
+
+func TestPermanent_OutermostDecides(t *testing.T) {
+	err := kverrors.Permanent(kverrors.Wrap(kverrors.Retryable(io.EOF), "failed to read"))
+
+	require.False(t, kverrors.IsRetryable(err))
+	require.True(t, kverrors.IsPermanent(err))
+	require.Equal(t, []kverrors.Class{kverrors.ClassPermanent, kverrors.ClassRetryable}, kverrors.Classes(err))
+	require.True(t, errors.Is(err, io.EOF))
+}
+
+func TestRateLimited(t *testing.T) {
+	err := fmt.Errorf("sync: %w", kverrors.RateLimited(kverrors.New("too many requests"), 30*time.Second))
+
+	require.True(t, kverrors.IsRetryable(err))
+	after, ok := kverrors.RetryAfter(err)
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, after)
+
+	_, ok = kverrors.RetryAfter(kverrors.Retryable(io.EOF))
+	require.False(t, ok)
+}
+
+func TestNotFoundAndConflict(t *testing.T) {
+	notFound := kverrors.NotFound(kverrors.New("pod not found", "name", "test"))
+	err := kverrors.Retryable(kverrors.Wrap(notFound, "failed to sync"))
+
+	require.True(t, kverrors.IsNotFound(err))
+	require.False(t, kverrors.IsConflict(err))
+	require.True(t, kverrors.IsRetryable(err))
+	require.Equal(t, "test", kverrors.KVs(notFound)["name"])
+
+	require.True(t, kverrors.IsConflict(kverrors.Conflict(io.EOF)))
+}
+
+func TestClasses_KeptByWith(t *testing.T) {
+	err := kverrors.With(kverrors.RateLimited(io.EOF, time.Minute), "attempt", 3)
+
+	after, ok := kverrors.RetryAfter(err)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, after)
+	require.True(t, errors.Is(err, io.EOF))
+}
+
+func TestClasses_KeepWrappedChain(t *testing.T) {
+	inner := kverrors.New("inner", "k", "v")
+	err := kverrors.Retryable(fmt.Errorf("outer: %w", inner))
+
+	require.True(t, kverrors.IsRetryable(err))
+	require.Equal(t, "outer: inner", err.Error())
+	require.Equal(t, inner, kverrors.Root(err))
+	require.Equal(t, map[string]interface{}{"k": "v"}, kverrors.MergeKVs(err, kverrors.PreferOuter).Values())
+}
+
+func TestClasses_Aggregate(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("is required"))
+	errs.Add(kverrors.Conflict(kverrors.New("already exists")))
+
+	require.True(t, kverrors.IsConflict(&errs))
+	require.False(t, kverrors.IsRetryable(&errs))
+}
+
+func TestClasses_StandardLibrary(t *testing.T) {
+	err := kverrors.Wrap(context.DeadlineExceeded, "failed to query")
+	require.True(t, kverrors.IsRetryable(err))
+
+	var netErr net.Error = &net.DNSError{Err: "timeout", IsTimeout: true}
+	require.True(t, kverrors.IsRetryable(kverrors.With(netErr, "host", "db")))
+
+	require.True(t, kverrors.IsPermanent(kverrors.Permanent(context.DeadlineExceeded)))
+	require.False(t, kverrors.IsRetryable(io.EOF))
+	require.Empty(t, kverrors.Classes(io.EOF))
+	require.Empty(t, kverrors.Classes(nil))
+}
+
+func TestClasses_Nil(t *testing.T) {
+	require.NoError(t, kverrors.Retryable(nil))
+	require.NoError(t, kverrors.RateLimited(nil, time.Second))
+}
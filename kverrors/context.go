@@ -0,0 +1,49 @@
+package kverrors
+
+import (
+	"context"
+)
+
+type contextKey struct{}
+
+// IntoContext returns a copy of ctx which carries keysAndValues. The key/value
+// pairs are appended to any Context already stored in ctx and are included in
+// every error created with NewFromContext or WrapFromContext.
+//
+// Example:
+//
+//	ctx = kverrors.IntoContext(ctx, "cluster", clusterName)
+//
+//	...
+//
+//	if err != nil {
+//	    return kverrors.WrapFromContext(ctx, err, "failed to get namespace")
+//	}
+func IntoContext(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	parent := FromContext(ctx)
+	c := make(Context, 0, len(parent)+len(keysAndValues))
+	c = append(c, parent...)
+	c = append(c, keysAndValues...)
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Context stored in ctx or nil if there is none
+func FromContext(ctx context.Context) Context {
+	c, _ := ctx.Value(contextKey{}).(Context)
+	return c
+}
+
+// NewFromContext creates a new KVError with keys and values and the Context
+// stored in ctx
+func NewFromContext(ctx context.Context, msg string, keysAndValues ...interface{}) error {
+	return withStack(newKVError(msg, append(keysAndValues, FromContext(ctx)...)), false)
+}
+
+// WrapFromContext wraps an error as a new error with keys and values and the
+// Context stored in ctx
+func WrapFromContext(ctx context.Context, err error, msg string, keysAndValues ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return withStack(wrapKVError(err, msg, append(keysAndValues, FromContext(ctx)...)), false)
+}
@@ -0,0 +1,47 @@
+package kverrors_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext_ReturnsNilWithoutContext(t *testing.T) {
+	require.Nil(t, kverrors.FromContext(context.Background()))
+}
+
+func TestIntoContext_AppendsToParentContext(t *testing.T) {
+	ctx := kverrors.IntoContext(context.Background(), "k1", "v1")
+	child := kverrors.IntoContext(ctx, "k2", "v2")
+
+	require.EqualValues(t, kverrors.NewContext("k1", "v1"), kverrors.FromContext(ctx))
+	require.EqualValues(t, kverrors.NewContext("k1", "v1", "k2", "v2"), kverrors.FromContext(child))
+}
+
+func TestNewFromContext_IncludesContextKeysAndValues(t *testing.T) {
+	ctx := kverrors.IntoContext(context.Background(), "foo", "bar")
+	err := kverrors.NewFromContext(ctx, t.Name(), "baz", "foo")
+
+	expected := map[string]interface{}{
+		kverrors.MessageKey: t.Name(),
+		"foo":               "bar",
+		"baz":               "foo",
+	}
+	require.EqualValues(t, expected, kverrors.KVs(err))
+}
+
+func TestWrapFromContext_IncludesContextKeysAndValues(t *testing.T) {
+	ctx := kverrors.IntoContext(context.Background(), "foo", "bar")
+	err := kverrors.WrapFromContext(ctx, io.ErrUnexpectedEOF, t.Name())
+
+	require.EqualValues(t, "bar", kverrors.KVs(err)["foo"])
+	require.Equal(t, io.ErrUnexpectedEOF, kverrors.Unwrap(err))
+}
+
+func TestWrapFromContext_ReturnsNilWhenErrIsNil(t *testing.T) {
+	ctx := kverrors.IntoContext(context.Background(), "foo", "bar")
+	require.Nil(t, kverrors.WrapFromContext(ctx, nil, t.Name()))
+}
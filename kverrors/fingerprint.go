@@ -0,0 +1,73 @@
+package kverrors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Fingerprint returns a stable hash identifying errors with the same cause
+// chain. It is computed from the structure of the chain, the messages and the
+// key names of every *KVError and the Go types of every other error. Values
+// are ignored so errors created at the same place with different key/values
+// have the same fingerprint. It returns an empty string for nil errors.
+func Fingerprint(err error) string {
+	return fingerprint(err, false)
+}
+
+// FingerprintWithMessages is like Fingerprint but also hashes the messages of
+// errors which are not a *KVError and do not wrap other errors, so sentinel
+// errors of the same Go type like io.EOF and io.ErrUnexpectedEOF are told
+// apart. Only use it if these messages do not contain values, otherwise every
+// occurrence of an error gets its own fingerprint.
+func FingerprintWithMessages(err error) string {
+	return fingerprint(err, true)
+}
+
+func fingerprint(err error, withMessages bool) string {
+	if err == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	_ = Walk(err, func(n Node) error {
+		_, _ = io.WriteString(h, strconv.Itoa(n.Depth))
+		switch e := n.Err.(type) {
+		case *KVError:
+			_, _ = fmt.Fprintf(h, "|%v", e.kv[MessageKey])
+			if e.template != nil {
+				_, _ = fmt.Fprintf(h, "|%s", e.template.code)
+			}
+			writeKeys(h, e.kv)
+		case *Aggregate:
+			_, _ = io.WriteString(h, "|aggregate")
+			for _, item := range e.items {
+				writeKeys(h, item.KVs)
+			}
+		default:
+			_, _ = fmt.Fprintf(h, "|%T", e)
+			if withMessages && len(Children(e)) == 0 {
+				_, _ = fmt.Fprintf(h, "|%s", e.Error())
+			}
+		}
+		_, _ = io.WriteString(h, "\n")
+		return nil
+	})
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// writeKeys writes the sorted keys of kvs except the message and cause keys
+func writeKeys(w io.Writer, kvs map[string]interface{}) {
+	keys := make([]string, 0, len(kvs))
+	for k := range kvs {
+		if k != MessageKey && k != CauseKey {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(w, "|%s", k)
+	}
+}
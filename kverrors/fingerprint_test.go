@@ -0,0 +1,74 @@
+package kverrors_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint_IgnoresValues(t *testing.T) {
+	newErr := func(name string, port int) error {
+		_, cause := os.Open(name)
+		return kverrors.Wrap(fmt.Errorf("open: %w", cause), "failed to load", "name", name, "port", port)
+	}
+
+	fp := kverrors.Fingerprint(newErr("a.json", 1))
+	require.Len(t, fp, 16)
+	require.Equal(t, fp, kverrors.Fingerprint(newErr("b.json", 2)))
+}
+
+func TestFingerprint_Differs(t *testing.T) {
+	base := kverrors.Fingerprint(kverrors.Wrap(io.EOF, "failed to read", "file", "a.json"))
+
+	for _, err := range []error{
+		kverrors.Wrap(io.EOF, "failed to write", "file", "a.json"),
+		kverrors.Wrap(io.EOF, "failed to read", "path", "a.json"),
+		kverrors.Wrap(io.EOF, "failed to read", "file", "a.json", "offset", 1),
+		kverrors.Wrap(&os.PathError{Op: "read", Path: "a.json", Err: io.EOF}, "failed to read", "file", "a.json"),
+		kverrors.New("failed to read", "file", "a.json"),
+		kverrors.Wrap(kverrors.Wrap(io.EOF, "failed to read", "file", "a.json"), "outer"),
+	} {
+		require.NotEqual(t, base, kverrors.Fingerprint(err), err.Error())
+	}
+}
+
+func TestFingerprint_ForeignErrors(t *testing.T) {
+	// messages of foreign errors may contain values
+	require.Equal(t, kverrors.Fingerprint(errors.New("disk full")), kverrors.Fingerprint(errors.New("permission denied")))
+	require.Equal(t, kverrors.Fingerprint(io.EOF), kverrors.Fingerprint(io.ErrUnexpectedEOF))
+}
+
+func TestFingerprintWithMessages(t *testing.T) {
+	newErr := func(cause error, name string) error {
+		return kverrors.Wrap(cause, "failed to read", "file", name)
+	}
+
+	require.Equal(t, kverrors.FingerprintWithMessages(newErr(io.EOF, "a.json")), kverrors.FingerprintWithMessages(newErr(io.EOF, "b.json")))
+	require.NotEqual(t, kverrors.FingerprintWithMessages(newErr(io.EOF, "a.json")), kverrors.FingerprintWithMessages(newErr(io.ErrUnexpectedEOF, "a.json")))
+	require.NotEqual(t, kverrors.Fingerprint(io.EOF), kverrors.FingerprintWithMessages(io.EOF))
+	require.Empty(t, kverrors.FingerprintWithMessages(nil))
+}
+
+func TestFingerprint_Aggregate(t *testing.T) {
+	newErr := func(value interface{}) error {
+		var errs kverrors.Aggregate
+		errs.Add(kverrors.New("is required"), "field", value)
+		errs.Add(io.EOF)
+		return &errs
+	}
+	require.Equal(t, kverrors.Fingerprint(newErr("spec.name")), kverrors.Fingerprint(newErr("spec.kind")))
+
+	var other kverrors.Aggregate
+	other.Add(kverrors.New("is required"), "path", "spec.name")
+	other.Add(io.EOF)
+	require.NotEqual(t, kverrors.Fingerprint(newErr("spec.name")), kverrors.Fingerprint(&other))
+}
+
+func TestFingerprint_Nil(t *testing.T) {
+	require.Empty(t, kverrors.Fingerprint(nil))
+}
@@ -0,0 +1,95 @@
+package kverrors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Format implements fmt.Formatter. %s and %v print the same as Error and %q
+// prints it quoted. %+v prints every error of the chain on its own line,
+// each followed by its sorted key/values and the captured stack trace:
+//
+//	failed to update cluster
+//	    cluster=prod
+//	caused by: failed to get namespace
+//	    namespace=default
+//	    stack trace:
+//	        github.com/example/pkg.getNamespace
+//	            /src/pkg/namespace.go:42
+//	caused by: connection refused
+func (e *KVError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			writeDetails(s, e)
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(%T=%s)", verb, e, e.Error())
+	}
+}
+
+func writeDetails(w io.Writer, err error) {
+	// errors of branches are indented further than the error they unwrap from
+	var indents []string
+	_ = Walk(err, func(n Node) error {
+		indent, prefix := "", ""
+		if n.Depth > 0 {
+			indent, prefix = indents[n.Depth-1], "caused by: "
+			if len(Children(n.Parent)) > 1 {
+				indent += "    "
+			}
+		}
+		indents = append(indents[:n.Depth], indent)
+
+		_, _ = fmt.Fprintf(w, "%s%s%s\n", indent, prefix, LinkMessage(n.Err))
+
+		kve, ok := n.Err.(*KVError)
+		if !ok {
+			return nil
+		}
+
+		keys := make([]string, 0, len(kve.kv))
+		for k := range kve.kv {
+			if k != MessageKey && k != CauseKey {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			_, _ = fmt.Fprintf(w, "%s    %s=%v\n", indent, k, kve.kv[k])
+		}
+
+		if kve.stack != nil {
+			_, _ = fmt.Fprintf(w, "%s    stack trace:\n", indent)
+			for _, frame := range kve.stack.Frames() {
+				_, _ = fmt.Fprintf(w, "%s        %s\n%s            %s:%d\n", indent, frame.Function, indent, frame.File, frame.Line)
+			}
+		}
+		return nil
+	})
+}
+
+// LinkMessage returns the message of err without the message of its cause.
+// Unlike Message, it never looks further down the chain. For errors which
+// are not a *KVError, such as those created with fmt.Errorf("...: %w", err),
+// the text of the cause is trimmed from the end of the error text.
+func LinkMessage(err error) string {
+	if kve, ok := err.(*KVError); ok {
+		return fmt.Sprint(kve.kv[MessageKey])
+	}
+
+	msg := err.Error()
+	if cause := Unwrap(err); cause != nil {
+		msg = strings.TrimSuffix(msg, cause.Error())
+		msg = strings.TrimRight(msg, ": ")
+	}
+	return msg
+}
@@ -0,0 +1,70 @@
+package kverrors_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVError_Format(t *testing.T) {
+	err := kverrors.Wrap(io.ErrUnexpectedEOF, "failed to read", "file", "a.txt")
+
+	require.Equal(t, err.Error(), fmt.Sprintf("%v", err))
+	require.Equal(t, err.Error(), fmt.Sprintf("%s", err))
+	require.Equal(t, fmt.Sprintf("%q", err.Error()), fmt.Sprintf("%q", err))
+}
+
+func TestKVError_Format_Details(t *testing.T) {
+	root := kverrors.New("connection refused", "port", 443)
+	middle := fmt.Errorf("request failed: %w", root)
+	err := kverrors.Wrap(middle, "failed to update cluster", "namespace", "default", "cluster", "prod")
+
+	expected := `failed to update cluster
+    cluster=prod
+    namespace=default
+caused by: request failed
+caused by: connection refused
+    port=443
+`
+	require.Equal(t, expected, fmt.Sprintf("%+v", err))
+}
+
+func TestKVError_Format_DetailsWithForeignCause(t *testing.T) {
+	err := kverrors.Wrap(io.ErrClosedPipe, "failed to write")
+
+	expected := `failed to write
+caused by: io: read/write on closed pipe
+`
+	require.Equal(t, expected, fmt.Sprintf("%+v", err))
+}
+
+func TestKVError_Format_DetailsWithStackTrace(t *testing.T) {
+	err := kverrors.NewWithStack("an error")
+
+	details := fmt.Sprintf("%+v", err)
+	require.True(t, strings.HasPrefix(details, "an error\n    stack trace:\n        "), details)
+	require.Contains(t, details, "TestKVError_Format_DetailsWithStackTrace\n            ")
+	require.Contains(t, details, "format_test.go:")
+}
+
+func TestKVError_Format_DetailsWithTree(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.Wrap(io.EOF, "failed to read", "file", "a.txt"))
+	errs.Add(kverrors.New("is required", "field", "spec.name"))
+	err := kverrors.Wrap(&errs, "invalid spec", "kind", "Pod")
+
+	expected := `invalid spec
+    kind=Pod
+caused by: 2 errors occurred: failed to read: EOF; is required
+    caused by: failed to read
+        file=a.txt
+    caused by: EOF
+    caused by: is required
+        field=spec.name
+`
+	require.Equal(t, expected, fmt.Sprintf("%+v", err))
+}
@@ -1,7 +1,6 @@
 package kverrors
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -16,13 +15,18 @@ const (
 
 // New creates a new KVError with keys and values
 func New(msg string, keysAndValues ...interface{}) error {
-	keysAndValues = append([]interface{}{MessageKey, msg}, keysAndValues...)
-	return &KVError{kv: kv.ToMap(keysAndValues...)}
+	return withStack(newKVError(msg, keysAndValues), false)
+}
+
+// NewWithStack creates a new KVError with keys and values and captures the
+// stack trace regardless of EnableStackTraces
+func NewWithStack(msg string, keysAndValues ...interface{}) error {
+	return withStack(newKVError(msg, keysAndValues), true)
 }
 
 // NewCtx creates a new error with Context
 func NewCtx(msg string, ctx Context, keysAndValues ...interface{}) error {
-	return New(msg, append(keysAndValues, ctx...)...)
+	return withStack(newKVError(msg, append(keysAndValues, ctx...)), false)
 }
 
 // Wrap wraps an error as a new error with keys and values
@@ -30,13 +34,35 @@ func Wrap(err error, msg string, keysAndValues ...interface{}) error {
 	if err == nil {
 		return nil
 	}
-	e := New(msg, append(keysAndValues, []interface{}{CauseKey, err}...)...)
-	return e
+	return withStack(wrapKVError(err, msg, keysAndValues), false)
+}
+
+// WrapWithStack wraps an error as a new error with keys and values and
+// captures the stack trace regardless of EnableStackTraces
+func WrapWithStack(err error, msg string, keysAndValues ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return withStack(wrapKVError(err, msg, keysAndValues), true)
+}
+
+func newKVError(msg string, keysAndValues []interface{}) *KVError {
+	keysAndValues = append([]interface{}{MessageKey, msg}, keysAndValues...)
+	return &KVError{kv: kv.ToValueMap(keysAndValues...)}
+}
+
+func wrapKVError(err error, msg string, keysAndValues []interface{}) *KVError {
+	return newKVError(msg, append(keysAndValues, []interface{}{CauseKey, err}...))
 }
 
 // KVError is an error that contains structured keys and values
 type KVError struct {
-	kv map[string]interface{}
+	kv       map[string]interface{}
+	stack    StackTrace
+	template *Template
+	classes  []classMark
+	// base is the error annotated with With
+	base error
 }
 
 // KVs returns the key/value pairs associated with this error if it is a *KVError
@@ -76,6 +102,10 @@ func (e *KVError) Unwrap() error {
 // Error returns the string formatted error message. This is required
 // to function as a standard library error
 func (e *KVError) Error() string {
+	// errors annotated with With keep the text of the annotated error
+	if e.base != nil {
+		return e.base.Error()
+	}
 	base := e.Unwrap()
 	if base != nil {
 		return fmt.Sprintf("%s: %s", Message(e), base.Error())
@@ -94,21 +124,23 @@ func Message(err error) string {
 }
 
 // Add adds key/value pairs to an error and returns the error
-// WARNING: The original error is modified with this operation
+// WARNING: The original error is modified with this operation, use With to
+// annotate errors which may be shared
 func Add(err error, keyValuePairs ...interface{}) error {
 	var kve *KVError
 	if !errors.As(err, &kve) {
-		return New(err.Error(), keyValuePairs...)
+		return withStack(newKVError(err.Error(), keyValuePairs), false)
 	}
-	for k, v := range kv.ToMap(keyValuePairs...) {
+	for k, v := range kv.ToValueMap(keyValuePairs...) {
 		kve.kv[k] = v
 	}
 	return kve
 }
 
-// MarshalJSON implements json.Marshaler
+// MarshalJSON implements json.Marshaler. Causes and values which are errors
+// but not a *KVError are rendered as their message, see EnableErrorTypes.
 func (e *KVError) MarshalJSON() ([]byte, error) {
-	return json.Marshal(e.kv)
+	return kv.AppendJSON(nil, e.jsonObject(ErrorTypesEnabled()))
 }
 
 // AddCtx appends Context to the error
@@ -126,20 +158,21 @@ func Unwrap(err error) error {
 // information that will be used with any returned error
 //
 // Example:
-//   errCtx := kverrors.Context("cluster", clusterName,
-//       "namespace", namespace)
 //
-//   ...
+//	errCtx := kverrors.Context("cluster", clusterName,
+//	    "namespace", namespace)
+//
+//	...
 //
-//   if err != nil {
-//       return kverrors.Wrap(err, "failed to get namespace").Ctx(errCtx)
-//   }
+//	if err != nil {
+//	    return kverrors.Wrap(err, "failed to get namespace").Ctx(errCtx)
+//	}
 //
-//   ...
+//	...
 //
-//   if err != nil {
-//       return kverrors.Wrap(err, "failed to update cluster").Ctx(errCtx)
-//   }
+//	if err != nil {
+//	    return kverrors.Wrap(err, "failed to update cluster").Ctx(errCtx)
+//	}
 func NewContext(keysAndValues ...interface{}) Context {
 	return keysAndValues
 }
@@ -151,19 +184,23 @@ type Context []interface{}
 
 // New creates a new KVError with this context
 func (c Context) New(msg string, keysAndValues ...interface{}) error {
-	return New(msg, append(keysAndValues, c...)...)
+	return withStack(newKVError(msg, append(keysAndValues, c...)), false)
 }
 
 // Wrap wraps an error with this context
 func (c Context) Wrap(err error, msg string, keysAndValues ...interface{}) error {
-	return Wrap(err, msg, append(keysAndValues, c...)...)
+	if err == nil {
+		return nil
+	}
+	return withStack(wrapKVError(err, msg, append(keysAndValues, c...)), false)
 }
 
-// Root unwraps the error until it reaches the root error
+// Root unwraps the error until it reaches the root error. For errors which
+// unwrap to multiple errors, like an *Aggregate, it returns the first root
+// error, see Leaves for all of them.
 func Root(err error) error {
-	root := err
-	for next := Unwrap(root); next != nil; next = Unwrap(root) {
-		root = next
+	if leaves := Leaves(err); len(leaves) > 0 {
+		return leaves[0]
 	}
-	return root
+	return nil
 }
@@ -0,0 +1,124 @@
+package kverrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrorTypeKey is the key of the Go type of errors which are not a *KVError,
+// see EnableErrorTypes
+const ErrorTypeKey string = "error_type"
+
+var errorTypes int32
+
+// EnableErrorTypes sets whether causes and values which are errors but not a
+// *KVError are marshaled to JSON as an object with their message and Go type
+// instead of only their message. It is disabled by default.
+func EnableErrorTypes(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&errorTypes, v)
+}
+
+// ErrorTypesEnabled reports whether error types are marshaled, see
+// EnableErrorTypes
+func ErrorTypesEnabled() bool {
+	return atomic.LoadInt32(&errorTypes) == 1
+}
+
+// jsonMap returns a copy of kvs with every error replaced by its JSON
+// representation
+func jsonMap(kvs map[string]interface{}, withTypes bool) map[string]interface{} {
+	m := make(map[string]interface{}, len(kvs))
+	for k, v := range kvs {
+		if err, ok := v.(error); ok {
+			m[k] = JSONValue(err, withTypes)
+			continue
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// jsonObject returns the JSON representation of the key/values of e with the
+// code of its template under CodeKey
+func (e *KVError) jsonObject(withTypes bool) map[string]interface{} {
+	m := jsonMap(e.kv, withTypes)
+	delete(m, CodeKey)
+	if e.template != nil {
+		m[CodeKey] = e.template.code
+	}
+	return m
+}
+
+// JSONValue returns the JSON representation of err which *KVError and
+// *Aggregate are marshaled to. A *KVError becomes an object with its
+// key/values and its cause nested under the cause key, an *Aggregate an array
+// with one object per error. Errors wrapping a *KVError, such as those created
+// with fmt.Errorf("...: %w", err), become a link of the cause chain so the
+// key/values of the wrapped error are kept. The causes of errors which unwrap
+// to multiple errors become an array. Other errors implementing
+// json.Marshaler are returned unchanged and any other error is rendered as its
+// message. If withTypes is set, errors which are not a *KVError carry their Go
+// type, see EnableErrorTypes.
+func JSONValue(err error, withTypes bool) interface{} {
+	switch e := err.(type) {
+	case *KVError:
+		return e.jsonObject(withTypes)
+	case *Aggregate:
+		return e.jsonItems(withTypes)
+	case multiCause:
+		causes := make([]interface{}, 0, len(e))
+		for _, child := range e {
+			causes = append(causes, JSONValue(child, withTypes))
+		}
+		return causes
+	case json.Marshaler:
+		return e
+	}
+
+	if children := Children(err); hasKVError(children) {
+		m := map[string]interface{}{MessageKey: LinkMessage(err)}
+		if len(children) == 1 {
+			m[CauseKey] = JSONValue(children[0], withTypes)
+		} else {
+			causes := make([]interface{}, 0, len(children))
+			for _, child := range children {
+				causes = append(causes, JSONValue(child, withTypes))
+			}
+			m[CauseKey] = causes
+		}
+		if withTypes {
+			m[ErrorTypeKey] = fmt.Sprintf("%T", err)
+		}
+		return m
+	}
+
+	if withTypes {
+		return map[string]interface{}{
+			MessageKey:   err.Error(),
+			ErrorTypeKey: fmt.Sprintf("%T", err),
+		}
+	}
+	return err.Error()
+}
+
+// hasKVError reports whether the chain of any of errs contains a *KVError
+func hasKVError(errs []error) bool {
+	for _, err := range errs {
+		found := false
+		_ = Walk(err, func(n Node) error {
+			if _, found = n.Err.(*KVError); found {
+				return SkipAll
+			}
+			return nil
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
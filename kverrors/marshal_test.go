@@ -0,0 +1,125 @@
+package kverrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON_ForeignCause(t *testing.T) {
+	_, cause := os.Open("does-not-exist")
+	err := kverrors.Wrap(cause, "failed to load config", "component", "loader")
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `{
+		"msg": "failed to load config",
+		"component": "loader",
+		"cause": "open does-not-exist: no such file or directory"
+	}`, string(b))
+}
+
+func TestMarshalJSON_ErrorValue(t *testing.T) {
+	err := kverrors.New("retry failed", "last_error", io.ErrUnexpectedEOF)
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `{"msg": "retry failed", "last_error": "unexpected EOF"}`, string(b))
+}
+
+func TestMarshalJSON_NestedChain(t *testing.T) {
+	root := kverrors.Wrap(io.EOF, "failed to read", "file", "a.json")
+	foreign := fmt.Errorf("decode: %w", root)
+	err := kverrors.Wrap(foreign, "failed to load config")
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `{
+		"msg": "failed to load config",
+		"cause": {
+			"msg": "decode",
+			"cause": {
+				"msg": "failed to read",
+				"file": "a.json",
+				"cause": "EOF"
+			}
+		}
+	}`, string(b))
+}
+
+func TestMarshalJSON_ErrorTypes(t *testing.T) {
+	kverrors.EnableErrorTypes(true)
+	defer kverrors.EnableErrorTypes(false)
+
+	_, cause := os.Open("does-not-exist")
+	err := kverrors.Wrap(fmt.Errorf("load: %w", kverrors.Wrap(cause, "failed to open")), "failed to start")
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `{
+		"msg": "failed to start",
+		"cause": {
+			"msg": "load",
+			"error_type": "*fmt.wrapError",
+			"cause": {
+				"msg": "failed to open",
+				"cause": {
+					"msg": "open does-not-exist: no such file or directory",
+					"error_type": "*fs.PathError"
+				}
+			}
+		}
+	}`, string(b))
+}
+
+func TestMarshalJSON_AggregateForeignItems(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(fmt.Errorf("field: %w", kverrors.New("must not be empty", "field", "name")))
+	errs.Add(io.EOF, "last_error", io.ErrUnexpectedEOF)
+
+	b, jerr := json.Marshal(&errs)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `[
+		{"msg": "field", "cause": {"msg": "must not be empty", "field": "name"}},
+		{"msg": "EOF", "last_error": "unexpected EOF"}
+	]`, string(b))
+}
+
+func TestMarshalJSON_Tree(t *testing.T) {
+	err := kverrors.Wrap(errors.Join(kverrors.New("a", "key", "a"), io.EOF), "outer")
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `{
+		"msg": "outer",
+		"cause": {
+			"msg": "a\nEOF",
+			"cause": [{"msg": "a", "key": "a"}, "EOF"]
+		}
+	}`, string(b))
+}
+
+func TestJSONValue(t *testing.T) {
+	err := kverrors.Wrap(fmt.Errorf("read: %w", io.EOF), "failed to load", "file", "a.json")
+
+	require.Equal(t, map[string]interface{}{
+		kverrors.MessageKey: "failed to load",
+		kverrors.CauseKey:   "read: EOF",
+		"file":              "a.json",
+	}, kverrors.JSONValue(err, false))
+	require.Equal(t, map[string]interface{}{
+		kverrors.MessageKey: "failed to load",
+		kverrors.CauseKey: map[string]interface{}{
+			kverrors.MessageKey:   "read: EOF",
+			kverrors.ErrorTypeKey: "*fmt.wrapError",
+		},
+		"file": "a.json",
+	}, kverrors.JSONValue(err, true))
+	require.Equal(t, "EOF", kverrors.JSONValue(io.EOF, false))
+}
@@ -0,0 +1,118 @@
+package kverrors
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MergePolicy decides which value is kept if the same key is attached to
+// several errors of a chain
+type MergePolicy int
+
+const (
+	// PreferOuter keeps the value closest to the outermost error
+	PreferOuter MergePolicy = iota
+	// PreferInner keeps the value closest to the root cause
+	PreferInner
+	// SuffixDepth keeps the value closest to the outermost error under the
+	// key and every other value under the key suffixed with its depth, for
+	// example "name.2". Values of the same depth are suffixed with a counter
+	// as well, for example "name.2.1"
+	SuffixDepth
+)
+
+// ChainKV is a key/value attached to an error of a chain
+type ChainKV struct {
+	Value interface{}
+	// Depth is the distance to the outermost error, which has a depth of 0
+	Depth int
+	// Origin is the error the key/value is attached to
+	Origin error
+}
+
+// ChainKVs are the merged key/values of an error chain
+type ChainKVs map[string]ChainKV
+
+// Values returns the merged key/values without their depth and origin
+func (c ChainKVs) Values() map[string]interface{} {
+	res := make(map[string]interface{}, len(c))
+	for k, v := range c {
+		res[k] = v.Value
+	}
+	return res
+}
+
+// MergeKVs returns the key/values attached to every error of the chain of err
+// merged according to policy. Unlike KVs, it follows the whole chain including
+// errors which are not a *KVError, such as those created with
+// fmt.Errorf("...: %w", err), and every branch of errors which unwrap to
+// multiple errors, see Walk. The key/values of the items of an Aggregate are attached
+// to the item errors. The message and cause keys are omitted.
+func MergeKVs(err error, policy MergePolicy) ChainKVs {
+	// collect every value of a key in walk order first: branches of a tree are
+	// walked depth first, so the first value found is not the outermost one
+	var keys []string
+	all := map[string][]ChainKV{}
+	add := func(k string, v ChainKV) {
+		if _, ok := all[k]; !ok {
+			keys = append(keys, k)
+		}
+		all[k] = append(all[k], v)
+	}
+
+	_ = Walk(err, func(n Node) error {
+		depth := n.Depth
+		switch e := n.Err.(type) {
+		case *KVError:
+			for k, v := range e.kv {
+				if k != MessageKey && k != CauseKey {
+					add(k, ChainKV{Value: v, Depth: depth, Origin: e})
+				}
+			}
+		case *Aggregate:
+			for _, item := range e.items {
+				for k, v := range item.KVs {
+					add(k, ChainKV{Value: v, Depth: depth + 1, Origin: item.Err})
+				}
+			}
+		}
+		return nil
+	})
+
+	res := make(ChainKVs, len(keys))
+	taken := func(k string) bool {
+		_, inRes := res[k]
+		_, inChain := all[k]
+		return inRes || inChain
+	}
+	for _, k := range keys {
+		values := all[k]
+		// stable, so values of the same depth keep their walk order
+		sort.SliceStable(values, func(i, j int) bool {
+			if policy == PreferInner {
+				return values[i].Depth > values[j].Depth
+			}
+			return values[i].Depth < values[j].Depth
+		})
+		res[k] = values[0]
+		if policy == SuffixDepth {
+			for _, v := range values[1:] {
+				res[suffixedKey(k, v.Depth, taken)] = v
+			}
+		}
+	}
+
+	return res
+}
+
+// suffixedKey returns k suffixed with depth, for example "name.2". If the key
+// is taken, it is suffixed with a counter as well, for example "name.2.1".
+func suffixedKey(k string, depth int, taken func(string) bool) string {
+	key := fmt.Sprintf("%s.%d", k, depth)
+	for i := 1; ; i++ {
+		if !taken(key) {
+			return key
+		}
+		key = fmt.Sprintf("%s.%d.%d", k, depth, i)
+	}
+}
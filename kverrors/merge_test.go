@@ -0,0 +1,95 @@
+package kverrors_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeKVs_PreferOuter(t *testing.T) {
+	root := kverrors.New("root", "name", "inner", "size", 1)
+	foreign := fmt.Errorf("foreign: %w", root)
+	err := kverrors.Wrap(foreign, "outer", "name", "outer")
+
+	kvs := kverrors.MergeKVs(err, kverrors.PreferOuter)
+	require.Equal(t, map[string]interface{}{"name": "outer", "size": 1}, kvs.Values())
+	require.Equal(t, 0, kvs["name"].Depth)
+	require.Equal(t, err, kvs["name"].Origin)
+	require.Equal(t, 2, kvs["size"].Depth)
+	require.Equal(t, root, kvs["size"].Origin)
+}
+
+func TestMergeKVs_PreferInner(t *testing.T) {
+	root := kverrors.New("root", "name", "inner")
+	err := kverrors.Wrap(root, "outer", "name", "outer", "size", 1)
+
+	kvs := kverrors.MergeKVs(err, kverrors.PreferInner)
+	require.Equal(t, map[string]interface{}{"name": "inner", "size": 1}, kvs.Values())
+	require.Equal(t, 1, kvs["name"].Depth)
+}
+
+func TestMergeKVs_SuffixDepth(t *testing.T) {
+	root := kverrors.New("root", "name", "inner")
+	err := kverrors.Wrap(root, "outer", "name", "outer")
+
+	kvs := kverrors.MergeKVs(err, kverrors.SuffixDepth)
+	require.Equal(t, map[string]interface{}{"name": "outer", "name.1": "inner"}, kvs.Values())
+}
+
+func TestMergeKVs_PreferOuter_Tree(t *testing.T) {
+	deep := kverrors.New("deep", "k", "deep")
+	shallow := kverrors.New("shallow", "k", "shallow")
+	err := errors.Join(kverrors.Wrap(deep, "mid"), shallow)
+
+	kvs := kverrors.MergeKVs(err, kverrors.PreferOuter)
+	require.Equal(t, map[string]interface{}{"k": "shallow"}, kvs.Values())
+	require.Equal(t, 1, kvs["k"].Depth)
+	require.Equal(t, shallow, kvs["k"].Origin)
+
+	kvs = kverrors.MergeKVs(err, kverrors.PreferInner)
+	require.Equal(t, map[string]interface{}{"k": "deep"}, kvs.Values())
+	require.Equal(t, 2, kvs["k"].Depth)
+}
+
+func TestMergeKVs_SuffixDepth_Tree(t *testing.T) {
+	err := errors.Join(
+		kverrors.New("a", "k", 1),
+		kverrors.New("b", "k", 2),
+		kverrors.New("c", "k", 3, "k.1", "taken"),
+	)
+
+	kvs := kverrors.MergeKVs(err, kverrors.SuffixDepth)
+	require.Equal(t, map[string]interface{}{
+		"k":     1,
+		"k.1":   "taken",
+		"k.1.1": 2,
+		"k.1.2": 3,
+	}, kvs.Values())
+}
+
+func TestMergeKVs_Aggregate(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("must not be negative", "value", -1), "field", "spec.replicas")
+	errs.Add(io.EOF, "index", 2)
+	err := kverrors.Wrap(&errs, "invalid spec", "name", "test")
+
+	kvs := kverrors.MergeKVs(err, kverrors.PreferOuter)
+	require.Equal(t, map[string]interface{}{
+		"name":  "test",
+		"field": "spec.replicas",
+		"value": -1,
+		"index": 2,
+	}, kvs.Values())
+	require.Equal(t, 2, kvs["field"].Depth)
+	require.Equal(t, 2, kvs["value"].Depth)
+	require.Equal(t, io.EOF, kvs["index"].Origin)
+}
+
+func TestMergeKVs_Nil(t *testing.T) {
+	require.Empty(t, kverrors.MergeKVs(nil, kverrors.PreferOuter))
+	require.Empty(t, kverrors.MergeKVs(io.EOF, kverrors.PreferOuter))
+}
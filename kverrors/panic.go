@@ -0,0 +1,82 @@
+package kverrors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ErrPanic is the template of errors created from recovered panics, see
+// FromPanic
+var ErrPanic = Define("Panic", "recovered from panic")
+
+// Keys used to describe recovered panics
+const (
+	PanicValueKey  = "panic_value"
+	PanicFuncKey   = "panic_func"
+	PanicCallerKey = "panic_caller"
+)
+
+// FromPanic converts a value returned by recover into an error created from
+// ErrPanic. The error carries the panic value, the function and file:line
+// which panicked and the stack trace of the goroutine starting at the panic,
+// regardless of EnableStackTraces. Panic values which are errors become the
+// cause of the error. It returns nil if v is nil.
+//
+// Example:
+//
+//	defer func() {
+//	    if err := kverrors.FromPanic(recover()); err != nil {
+//	        ...
+//	    }
+//	}()
+func FromPanic(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	var pcs [maxStackDepth]uintptr
+	// skip runtime.Callers and FromPanic
+	n := runtime.Callers(2, pcs[:])
+	stack := panicStack(pcs[:n])
+
+	var kvs []interface{}
+	cause, ok := v.(error)
+	if !ok {
+		kvs = append(kvs, PanicValueKey, fmt.Sprint(v))
+	}
+	if frames := stack.Frames(); len(frames) > 0 {
+		kvs = append(kvs,
+			PanicFuncKey, frames[0].Function,
+			PanicCallerKey, fmt.Sprintf("%s:%d", frames[0].File, frames[0].Line),
+		)
+	}
+
+	e := ErrPanic.newKVError(cause, kvs)
+	e.stack = stack
+	return e
+}
+
+// panicStack returns the frames of pcs below the runtime frames raising the
+// panic or all of them if pcs was not captured while panicking
+func panicStack(pcs []uintptr) StackTrace {
+	start := -1
+	for i, pc := range pcs {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			continue
+		}
+		switch {
+		case fn.Name() == "runtime.gopanic":
+			start = i + 1
+		case start == i && strings.HasPrefix(fn.Name(), "runtime."):
+			// runtime errors like nil pointer dereferences panic from
+			// within the runtime
+			start = i + 1
+		}
+	}
+	if start < 0 || start >= len(pcs) {
+		return append(StackTrace(nil), pcs...)
+	}
+	return append(StackTrace(nil), pcs[start:]...)
+}
@@ -0,0 +1,59 @@
+package kverrors_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func recoverPanic(fn func()) (err error) {
+	defer func() {
+		err = kverrors.FromPanic(recover())
+	}()
+	fn()
+	return nil
+}
+
+func panicWith(v interface{}) {
+	panic(v)
+}
+
+func TestFromPanic(t *testing.T) {
+	err := recoverPanic(func() { panicWith("boom") })
+
+	require.True(t, errors.Is(err, kverrors.ErrPanic))
+	require.Equal(t, "Panic", kverrors.Code(err))
+	require.Equal(t, "recovered from panic", err.Error())
+
+	kvs := kverrors.KVs(err)
+	require.Equal(t, "boom", kvs[kverrors.PanicValueKey])
+	require.Equal(t, "github.com/ViaQ/logerr/v2/kverrors_test.panicWith", kvs[kverrors.PanicFuncKey])
+	require.Contains(t, kvs[kverrors.PanicCallerKey], "panic_test.go:")
+
+	frames := kverrors.Stack(err).Frames()
+	require.NotEmpty(t, frames)
+	require.Equal(t, "github.com/ViaQ/logerr/v2/kverrors_test.panicWith", frames[0].Function)
+}
+
+func TestFromPanic_ErrorValue(t *testing.T) {
+	err := recoverPanic(func() { panicWith(io.ErrUnexpectedEOF) })
+
+	require.True(t, errors.Is(err, kverrors.ErrPanic))
+	require.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	require.NotContains(t, kverrors.KVs(err), kverrors.PanicValueKey)
+}
+
+func TestFromPanic_RuntimeError(t *testing.T) {
+	var s []int
+	err := recoverPanic(func() { _ = s[1] })
+
+	require.Contains(t, err.Error(), "index out of range")
+	require.Contains(t, kverrors.KVs(err)[kverrors.PanicFuncKey], "TestFromPanic_RuntimeError")
+}
+
+func TestFromPanic_Nil(t *testing.T) {
+	require.NoError(t, kverrors.FromPanic(nil))
+}
@@ -0,0 +1,90 @@
+package kverrors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of frames of a captured stack trace
+const maxStackDepth = 32
+
+// stackTraces is 1 if stack traces are captured by every constructor
+var stackTraces int32
+
+// EnableStackTraces enables or disables capturing the stack trace in every
+// New, Wrap and Context.New call. Stack traces are disabled by default and
+// only captured by NewWithStack and WrapWithStack then.
+func EnableStackTraces(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&stackTraces, v)
+}
+
+// StackTrace is the stack of program counters where an error was created
+type StackTrace []uintptr
+
+// Frames returns the resolved frames of the stack trace
+func (s StackTrace) Frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(s)
+	res := make([]runtime.Frame, 0, len(s))
+	for {
+		frame, more := frames.Next()
+		res = append(res, frame)
+		if !more {
+			return res
+		}
+	}
+}
+
+// String returns the stack trace in the format of runtime/debug.Stack:
+// one function per line followed by its indented file:line
+func (s StackTrace) String() string {
+	var b strings.Builder
+	for _, frame := range s.Frames() {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+	return b.String()
+}
+
+// StackTrace returns the stack trace captured when the error was created or
+// nil if none was captured
+func (e *KVError) StackTrace() StackTrace {
+	return e.stack
+}
+
+// Stack returns the stack trace captured closest to the root of the error
+// chain, since that points to where the error originated. It returns nil if
+// no error in the chain captured a stack trace.
+func Stack(err error) StackTrace {
+	var stack StackTrace
+	depth := -1
+	_ = Walk(err, func(n Node) error {
+		if kve, ok := n.Err.(*KVError); ok && kve.stack != nil && n.Depth > depth {
+			stack, depth = kve.stack, n.Depth
+		}
+		return nil
+	})
+	return stack
+}
+
+// withStack captures the stack trace starting at the caller of the function
+// calling withStack if force is set or stack traces are enabled. It must be
+// called directly by the exported constructors.
+func withStack(e *KVError, force bool) *KVError {
+	if !force && atomic.LoadInt32(&stackTraces) == 0 {
+		return e
+	}
+
+	var pcs [maxStackDepth]uintptr
+	// skip runtime.Callers, withStack and the constructor
+	n := runtime.Callers(3, pcs[:])
+	e.stack = append(StackTrace(nil), pcs[:n]...)
+	return e
+}
@@ -0,0 +1,81 @@
+package kverrors_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_DoesNotCaptureStackByDefault(t *testing.T) {
+	err := kverrors.New(t.Name())
+	require.Nil(t, kverrors.Stack(err))
+	require.Nil(t, err.(*kverrors.KVError).StackTrace())
+}
+
+func TestNewWithStack_CapturesCaller(t *testing.T) {
+	err := kverrors.NewWithStack(t.Name())
+
+	frames := kverrors.Stack(err).Frames()
+	require.NotEmpty(t, frames)
+	require.True(t, strings.HasSuffix(frames[0].Function, "TestNewWithStack_CapturesCaller"), frames[0].Function)
+	require.True(t, strings.HasSuffix(frames[0].File, "stack_test.go"), frames[0].File)
+}
+
+func TestWrapWithStack_ReturnsNilWhenErrIsNil(t *testing.T) {
+	require.Nil(t, kverrors.WrapWithStack(nil, t.Name()))
+}
+
+func TestEnableStackTraces(t *testing.T) {
+	kverrors.EnableStackTraces(true)
+	defer kverrors.EnableStackTraces(false)
+
+	errCtx := kverrors.NewContext("key", "value")
+	errs := map[string]error{
+		"New":         kverrors.New(t.Name()),
+		"NewCtx":      kverrors.NewCtx(t.Name(), errCtx),
+		"Wrap":        kverrors.Wrap(io.EOF, t.Name()),
+		"Context.New": errCtx.New(t.Name()),
+	}
+
+	for name, err := range errs {
+		frames := kverrors.Stack(err).Frames()
+		require.NotEmpty(t, frames, name)
+		require.True(t, strings.HasSuffix(frames[0].Function, "TestEnableStackTraces"), "%s: %s", name, frames[0].Function)
+	}
+}
+
+func TestStack_ReturnsStackClosestToRoot(t *testing.T) {
+	root := kverrors.NewWithStack("root")
+	err := kverrors.WrapWithStack(kverrors.Wrap(root, "middle"), "outer")
+
+	require.Equal(t, root.(*kverrors.KVError).StackTrace(), kverrors.Stack(err))
+}
+
+func TestStackTrace_String(t *testing.T) {
+	err := kverrors.NewWithStack(t.Name())
+
+	s := kverrors.Stack(err).String()
+	require.Contains(t, s, "TestStackTrace_String\n\t")
+	require.Contains(t, s, "stack_test.go:")
+}
+
+func BenchmarkNew(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		name := "StackTracesDisabled"
+		if enabled {
+			name = "StackTracesEnabled"
+		}
+		b.Run(name, func(b *testing.B) {
+			kverrors.EnableStackTraces(enabled)
+			defer kverrors.EnableStackTraces(false)
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = kverrors.New("an error", "key", "value")
+			}
+		})
+	}
+}
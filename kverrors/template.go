@@ -0,0 +1,117 @@
+package kverrors
+
+import (
+	"errors"
+	"sync"
+)
+
+// CodeKey is the key of the code of errors created from a Template when they
+// are marshaled to JSON. The code is not one of the key/values of the error and
+// the key is reserved: key/values of errors under CodeKey are not marshaled.
+const CodeKey string = "_code"
+
+// Template defines a class of errors with a stable code. Errors created from
+// a template carry their own key/values but match the template with
+// errors.Is. Templates are usually defined as package level variables.
+//
+// Example:
+//
+//	var ErrNotFound = kverrors.Define("NotFound", "resource not found")
+//
+//	...
+//
+//	return ErrNotFound.New("name", name, "namespace", namespace)
+//
+//	...
+//
+//	if errors.Is(err, ErrNotFound) {
+//	    ...
+//	}
+type Template struct {
+	code string
+	msg  string
+}
+
+var (
+	templatesLock sync.RWMutex
+	templates     = map[string]*Template{}
+)
+
+// Define creates a new Template with a code and the message of all errors
+// created from it. Errors decoded from JSON match the first template defined
+// with their code.
+func Define(code, msg string) *Template {
+	t := &Template{code: code, msg: msg}
+
+	templatesLock.Lock()
+	defer templatesLock.Unlock()
+	if _, ok := templates[code]; !ok {
+		templates[code] = t
+	}
+	return t
+}
+
+func lookupTemplate(code string) *Template {
+	templatesLock.RLock()
+	defer templatesLock.RUnlock()
+	return templates[code]
+}
+
+// Code returns the code of the template
+func (t *Template) Code() string {
+	return t.code
+}
+
+// Error returns the message of the template. This allows to use the template
+// as the target of errors.Is
+func (t *Template) Error() string {
+	return t.msg
+}
+
+// New creates a new KVError from the template with keys and values
+func (t *Template) New(keysAndValues ...interface{}) error {
+	return withStack(t.newKVError(nil, keysAndValues), false)
+}
+
+// Wrap wraps an error as a new KVError from the template with keys and values
+func (t *Template) Wrap(err error, keysAndValues ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return withStack(t.newKVError(err, keysAndValues), false)
+}
+
+func (t *Template) newKVError(err error, keysAndValues []interface{}) *KVError {
+	var e *KVError
+	if err != nil {
+		e = wrapKVError(err, t.msg, keysAndValues)
+	} else {
+		e = newKVError(t.msg, keysAndValues)
+	}
+	e.template = t
+	return e
+}
+
+// Is reports whether the error was created from target if it is a
+// *Template or whether target is the error annotated with With. This is
+// required to work with the standard library errors.Is
+func (e *KVError) Is(target error) bool {
+	if t, ok := target.(*Template); ok && e.template != nil && e.template == t {
+		return true
+	}
+	return e.base != nil && errors.Is(e.base, target)
+}
+
+// Code returns the code of the outermost error in the chain created from a
+// Template or an empty string if there is none
+func Code(err error) string {
+	code := ""
+	_ = Walk(err, func(n Node) error {
+		if kve, ok := n.Err.(*KVError); ok && kve.template != nil {
+			code = kve.template.code
+			return SkipAll
+		}
+		return nil
+	})
+	return code
+}
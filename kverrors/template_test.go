@@ -0,0 +1,76 @@
+package kverrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errNotFound = kverrors.Define("NotFound", "resource not found")
+	errConflict = kverrors.Define("Conflict", "resource conflict")
+)
+
+func TestTemplate_New(t *testing.T) {
+	err := errNotFound.New("name", "foo")
+
+	require.Equal(t, "resource not found", err.Error())
+	require.Equal(t, map[string]interface{}{
+		kverrors.MessageKey: "resource not found",
+		"name":              "foo",
+	}, kverrors.KVs(err))
+}
+
+func TestTemplate_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(errNotFound.New("code", 404))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"_code":"NotFound","code":404,"msg":"resource not found"}`, string(b))
+
+	// the code key is reserved so errors cannot pretend to be from a template
+	b, err = json.Marshal(kverrors.New("resource not found", kverrors.CodeKey, "NotFound"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"msg":"resource not found"}`, string(b))
+
+	var decoded kverrors.KVError
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.False(t, errors.Is(&decoded, errNotFound))
+	require.Empty(t, kverrors.Code(&decoded))
+}
+
+func TestTemplate_Wrap(t *testing.T) {
+	err := errNotFound.Wrap(io.EOF, "name", "foo")
+
+	require.Equal(t, "resource not found: EOF", err.Error())
+	require.True(t, errors.Is(err, io.EOF))
+	require.EqualValues(t, "foo", kverrors.KVs(err)["name"])
+	require.Nil(t, errNotFound.Wrap(nil))
+}
+
+func TestTemplate_Is(t *testing.T) {
+	err := errNotFound.New("name", "foo")
+
+	require.True(t, errors.Is(err, errNotFound))
+	require.False(t, errors.Is(err, errConflict))
+	require.False(t, errors.Is(kverrors.New("resource not found"), errNotFound))
+
+	wrapped := fmt.Errorf("lookup failed: %w", kverrors.Wrap(err, "failed to reconcile"))
+	require.True(t, errors.Is(wrapped, errNotFound))
+
+	// instances do not match each other, only the template
+	require.False(t, errors.Is(err, errNotFound.New("name", "foo")))
+}
+
+func TestCode(t *testing.T) {
+	require.Equal(t, "NotFound", kverrors.Code(errNotFound.New()))
+	require.Equal(t, "NotFound", kverrors.Code(kverrors.Wrap(errNotFound.New(), "outer")))
+	require.Equal(t, "Conflict", kverrors.Code(errConflict.Wrap(errNotFound.New())))
+	require.Equal(t, "NotFound", kverrors.Code(fmt.Errorf("outer: %w", errNotFound.New())))
+	require.Empty(t, kverrors.Code(kverrors.New("an error")))
+	require.Empty(t, kverrors.Code(io.EOF))
+	require.Equal(t, "NotFound", errNotFound.Code())
+}
@@ -0,0 +1,157 @@
+package kverrors
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ForeignError is the placeholder for an error which was not a *KVError when
+// it was marshaled to JSON, like an *os.PathError or an error created with
+// fmt.Errorf
+type ForeignError struct {
+	Msg string
+	// Type is the Go type of the original error if it was marshaled with
+	// EnableErrorTypes
+	Type  string
+	Cause error
+}
+
+// Error returns the message of the original error
+func (e *ForeignError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s", e.Msg, e.Cause.Error())
+	}
+	return e.Msg
+}
+
+// Unwrap returns the cause of the original error if it wrapped a *KVError
+func (e *ForeignError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target has the same message and, if known, the same Go
+// type as the original error. This allows to match sentinel errors like
+// io.EOF after decoding.
+func (e *ForeignError) Is(target error) bool {
+	if target == nil || e.Cause != nil || target.Error() != e.Msg {
+		return false
+	}
+	return e.Type == "" || e.Type == fmt.Sprintf("%T", target)
+}
+
+// MarshalJSON implements json.Marshaler using the same representation as the
+// original error
+func (e *ForeignError) MarshalJSON() ([]byte, error) {
+	if e.Type == "" && e.Cause == nil {
+		return json.Marshal(e.Msg)
+	}
+	m := map[string]interface{}{MessageKey: e.Msg}
+	if e.Type != "" {
+		m[ErrorTypeKey] = e.Type
+	}
+	if e.Cause != nil {
+		m[CauseKey] = JSONValue(e.Cause, ErrorTypesEnabled())
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It rebuilds the message,
+// key/values and cause chain of a marshaled *KVError. Causes which were not a
+// *KVError become a *ForeignError. Errors created from a Template match the
+// template defined with the same code with errors.Is.
+func (e *KVError) UnmarshalJSON(data []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	if m == nil {
+		return New("kverror is not a JSON object")
+	}
+	*e = *fromMap(m)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It rebuilds the errors of a
+// marshaled *Aggregate, see KVError.UnmarshalJSON. The key/values of the items
+// are part of the item errors.
+func (a *Aggregate) UnmarshalJSON(data []byte) error {
+	var items []interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	a.items = nil
+	for _, item := range items {
+		a.Add(FromValue(item))
+	}
+	return nil
+}
+
+// FromValue reconstructs an error from its JSON representation decoded into
+// an interface{}, for example with json.Unmarshal. Objects become a *KVError,
+// arrays an *Aggregate and strings a *ForeignError. It returns nil for any
+// other value and for empty objects.
+func FromValue(v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		if isForeign(v) {
+			msg, _ := v[MessageKey].(string)
+			typ, _ := v[ErrorTypeKey].(string)
+			return &ForeignError{Msg: msg, Type: typ, Cause: FromValue(v[CauseKey])}
+		}
+		return fromMap(v)
+	case []interface{}:
+		var errs Aggregate
+		for _, item := range v {
+			errs.Add(FromValue(item))
+		}
+		return errs.ErrorOrNil()
+	case string:
+		return &ForeignError{Msg: v}
+	default:
+		return nil
+	}
+}
+
+func fromMap(m map[string]interface{}) *KVError {
+	e := &KVError{kv: make(map[string]interface{}, len(m))}
+	for k, v := range m {
+		e.kv[k] = v
+	}
+
+	if _, ok := e.kv[MessageKey]; !ok {
+		e.kv[MessageKey] = ""
+	}
+	if cause, ok := e.kv[CauseKey]; ok {
+		if err := FromValue(cause); err != nil {
+			e.kv[CauseKey] = err
+		} else {
+			delete(e.kv, CauseKey)
+		}
+	}
+	if code, ok := e.kv[CodeKey].(string); ok {
+		// errors of templates which are not defined keep their code
+		e.template = lookupTemplate(code)
+		if e.template == nil {
+			e.template = &Template{code: code, msg: fmt.Sprint(e.kv[MessageKey])}
+		}
+	}
+	delete(e.kv, CodeKey)
+	return e
+}
+
+// isForeign reports whether m is the representation of an error which was
+// not a *KVError marshaled with EnableErrorTypes
+func isForeign(m map[string]interface{}) bool {
+	if _, ok := m[ErrorTypeKey]; !ok {
+		return false
+	}
+	for k := range m {
+		if k != MessageKey && k != ErrorTypeKey && k != CauseKey {
+			return false
+		}
+	}
+	return true
+}
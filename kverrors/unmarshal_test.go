@@ -0,0 +1,127 @@
+package kverrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func roundTrip(t *testing.T, err error) *kverrors.KVError {
+	t.Helper()
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+
+	var decoded kverrors.KVError
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	return &decoded
+}
+
+func TestUnmarshalJSON_Chain(t *testing.T) {
+	root := kverrors.New("connection refused", "host", "db")
+	err := kverrors.Wrap(kverrors.Wrap(root, "failed to query"), "failed to load user", "user", "alice")
+
+	decoded := roundTrip(t, err)
+	require.Equal(t, err.Error(), decoded.Error())
+	require.Equal(t, "failed to load user", kverrors.Message(decoded))
+	require.Equal(t, "alice", kverrors.KVs(decoded)["user"])
+
+	r := kverrors.Root(decoded)
+	require.Equal(t, "connection refused", kverrors.Message(r))
+	require.Equal(t, "db", kverrors.KVs(r)["host"])
+
+	var kve *kverrors.KVError
+	require.True(t, errors.As(decoded, &kve))
+}
+
+func TestUnmarshalJSON_ForeignCause(t *testing.T) {
+	err := kverrors.Wrap(io.EOF, "failed to read")
+
+	decoded := roundTrip(t, err)
+	require.Equal(t, "failed to read: EOF", decoded.Error())
+	require.True(t, errors.Is(decoded, io.EOF))
+
+	var foreign *kverrors.ForeignError
+	require.True(t, errors.As(decoded, &foreign))
+	require.Equal(t, "EOF", foreign.Msg)
+	require.Empty(t, foreign.Type)
+}
+
+func TestUnmarshalJSON_ForeignTypes(t *testing.T) {
+	kverrors.EnableErrorTypes(true)
+	defer kverrors.EnableErrorTypes(false)
+
+	_, cause := os.Open("does-not-exist")
+	err := kverrors.Wrap(fmt.Errorf("load: %w", kverrors.Wrap(cause, "failed to open")), "failed to start")
+
+	decoded := roundTrip(t, err)
+	require.Equal(t, err.Error(), decoded.Error())
+
+	var foreign *kverrors.ForeignError
+	require.True(t, errors.As(decoded, &foreign))
+	require.Equal(t, "load", foreign.Msg)
+	require.Equal(t, "*fmt.wrapError", foreign.Type)
+
+	root, ok := kverrors.Root(decoded).(*kverrors.ForeignError)
+	require.True(t, ok)
+	require.Equal(t, "*fs.PathError", root.Type)
+	require.False(t, errors.Is(decoded, io.EOF))
+
+	b, jerr := json.Marshal(decoded)
+	require.NoError(t, jerr)
+	expected, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, string(expected), string(b))
+}
+
+func TestUnmarshalJSON_Template(t *testing.T) {
+	tmpl := kverrors.Define("UnmarshalNotFound", "resource not found")
+	err := kverrors.Wrap(tmpl.New("kind", "Pod"), "failed to sync")
+
+	decoded := roundTrip(t, err)
+	require.True(t, errors.Is(decoded, tmpl))
+	require.Equal(t, "UnmarshalNotFound", kverrors.Code(decoded))
+	require.NotContains(t, kverrors.KVs(kverrors.Root(decoded)), kverrors.CodeKey)
+
+	// errors of templates which are not defined keep their code
+	var unknown kverrors.KVError
+	require.NoError(t, json.Unmarshal([]byte(`{"_code":"UnmarshalUnknown","msg":"unknown"}`), &unknown))
+	require.Equal(t, "UnmarshalUnknown", kverrors.Code(&unknown))
+	require.False(t, errors.Is(&unknown, tmpl))
+}
+
+func TestUnmarshalJSON_NotAnObject(t *testing.T) {
+	var decoded kverrors.KVError
+	require.Error(t, json.Unmarshal([]byte(`"EOF"`), &decoded))
+	require.Error(t, json.Unmarshal([]byte(`null`), &decoded))
+}
+
+func TestUnmarshalJSON_Aggregate(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("is required"), "field", "spec.name")
+	errs.Add(io.EOF)
+
+	b, err := json.Marshal(&errs)
+	require.NoError(t, err)
+
+	var decoded kverrors.Aggregate
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, 2, decoded.Len())
+	require.Equal(t, "spec.name", kverrors.KVs(decoded.Items()[0].Err)["field"])
+	require.Equal(t, "EOF", decoded.Items()[1].Err.Error())
+}
+
+func TestFromValue(t *testing.T) {
+	require.Nil(t, kverrors.FromValue(nil))
+	require.Nil(t, kverrors.FromValue(map[string]interface{}{}))
+	require.Nil(t, kverrors.FromValue(42.0))
+	require.Equal(t, &kverrors.ForeignError{Msg: "EOF"}, kverrors.FromValue("EOF"))
+
+	err := kverrors.FromValue(map[string]interface{}{"msg": "failed", "cause": "EOF"})
+	require.Equal(t, "failed: EOF", err.Error())
+}
@@ -0,0 +1,98 @@
+package kverrors
+
+import (
+	"errors"
+)
+
+var (
+	// SkipChildren is returned by a WalkFunc to skip the errors the visited
+	// error unwraps to
+	SkipChildren = errors.New("skip children")
+	// SkipAll is returned by a WalkFunc to stop the walk
+	SkipAll = errors.New("skip all")
+)
+
+// Node is an error visited by Walk
+type Node struct {
+	Err error
+	// Parent is the error which unwraps to Err or nil for the outermost error
+	Parent error
+	// Depth is the distance to the outermost error, which has a depth of 0
+	Depth int
+	// Index is the position of Err among the errors Parent unwraps to
+	Index int
+}
+
+// WalkFunc is called by Walk for every visited error
+type WalkFunc func(n Node) error
+
+// Walk calls fn for err and every error it unwraps to in depth-first order,
+// see Children. Unlike Unwrap, it visits every branch of errors which unwrap
+// to multiple errors, like an *Aggregate. If fn returns SkipChildren the
+// errors the visited error unwraps to are skipped, if it returns SkipAll or
+// any other error the walk stops. Walk returns the error returned by fn
+// except for SkipChildren and SkipAll.
+func Walk(err error, fn WalkFunc) error {
+	if err == nil {
+		return nil
+	}
+	if werr := walkNode(Node{Err: err}, fn); werr != SkipAll {
+		return werr
+	}
+	return nil
+}
+
+func walkNode(n Node, fn WalkFunc) error {
+	if err := fn(n); err != nil {
+		if err == SkipChildren {
+			return nil
+		}
+		return err
+	}
+	for i, child := range Children(n.Err) {
+		if err := walkNode(Node{Err: child, Parent: n.Err, Depth: n.Depth + 1, Index: i}, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Children returns the errors err unwraps to with either an Unwrap() error or
+// an Unwrap() []error method
+func Children(err error) []error {
+	switch e := err.(type) {
+	case *KVError:
+		if cause, ok := e.Unwrap().(multiCause); ok {
+			return Children(cause)
+		}
+		if cause := e.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	case interface{ Unwrap() []error }:
+		var res []error
+		for _, child := range e.Unwrap() {
+			if child != nil {
+				res = append(res, child)
+			}
+		}
+		return res
+	case interface{ Unwrap() error }:
+		if child := e.Unwrap(); child != nil {
+			return []error{child}
+		}
+	}
+	return nil
+}
+
+// Leaves returns the errors of the chain of err which do not unwrap to other
+// errors in depth-first order
+func Leaves(err error) []error {
+	var leaves []error
+	_ = Walk(err, func(n Node) error {
+		if len(Children(n.Err)) == 0 {
+			leaves = append(leaves, n.Err)
+		}
+		return nil
+	})
+	return leaves
+}
@@ -0,0 +1,94 @@
+package kverrors_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	a := kverrors.New("a")
+	b := kverrors.Wrap(io.EOF, "b")
+	join := errors.Join(a, nil, b)
+	err := kverrors.Wrap(fmt.Errorf("foreign: %w", join), "outer")
+
+	var visited []string
+	require.NoError(t, kverrors.Walk(err, func(n kverrors.Node) error {
+		parent := "<nil>"
+		if n.Parent != nil {
+			parent = kverrors.LinkMessage(n.Parent)
+		}
+		visited = append(visited, fmt.Sprintf("%d %d %s<-%s", n.Depth, n.Index, kverrors.LinkMessage(n.Err), parent))
+		return nil
+	}))
+
+	require.Equal(t, []string{
+		"0 0 outer<-<nil>",
+		"1 0 foreign<-outer",
+		"2 0 a\nb: EOF<-foreign",
+		"3 0 a<-a\nb: EOF",
+		"3 1 b<-a\nb: EOF",
+		"4 0 EOF<-b",
+	}, visited)
+}
+
+func TestWalk_Skip(t *testing.T) {
+	err := errors.Join(kverrors.Wrap(io.EOF, "a"), kverrors.Wrap(io.ErrUnexpectedEOF, "b"))
+
+	var visited []string
+	require.NoError(t, kverrors.Walk(err, func(n kverrors.Node) error {
+		visited = append(visited, kverrors.LinkMessage(n.Err))
+		if kverrors.LinkMessage(n.Err) == "a" {
+			return kverrors.SkipChildren
+		}
+		if n.Err == io.ErrUnexpectedEOF {
+			return kverrors.SkipAll
+		}
+		return nil
+	}))
+	require.Equal(t, []string{"a: EOF\nb: unexpected EOF", "a", "b", "unexpected EOF"}, visited)
+
+	stop := errors.New("stop")
+	require.Equal(t, stop, kverrors.Walk(err, func(n kverrors.Node) error {
+		return stop
+	}))
+	require.NoError(t, kverrors.Walk(nil, func(n kverrors.Node) error {
+		return stop
+	}))
+}
+
+func TestLeaves(t *testing.T) {
+	err := kverrors.Wrap(errors.Join(kverrors.Wrap(io.EOF, "a"), kverrors.New("b")), "outer")
+
+	leaves := kverrors.Leaves(err)
+	require.Len(t, leaves, 2)
+	require.Equal(t, io.EOF, leaves[0])
+	require.Equal(t, "b", leaves[1].Error())
+	require.Equal(t, io.EOF, kverrors.Root(err))
+
+	require.Equal(t, []error{io.EOF}, kverrors.Leaves(io.EOF))
+	require.Empty(t, kverrors.Leaves(nil))
+	require.Nil(t, kverrors.Root(nil))
+}
+
+func TestChildren(t *testing.T) {
+	require.Empty(t, kverrors.Children(io.EOF))
+	require.Empty(t, kverrors.Children(kverrors.New("a")))
+	require.Equal(t, []error{io.EOF}, kverrors.Children(kverrors.Wrap(io.EOF, "a")))
+	require.Equal(t, []error{io.EOF, io.ErrUnexpectedEOF}, kverrors.Children(errors.Join(io.EOF, nil, io.ErrUnexpectedEOF)))
+}
+
+func TestWalk_Trees(t *testing.T) {
+	tmpl := kverrors.Define("WalkConflict", "conflict")
+	err := kverrors.Wrap(errors.Join(
+		kverrors.New("a", "key", "a"),
+		kverrors.Wrap(tmpl.New("key", "b", "other", 1), "b"),
+	), "outer")
+
+	require.Equal(t, "WalkConflict", kverrors.Code(err))
+	require.Equal(t, map[string]interface{}{"key": "a", "other": 1}, kverrors.MergeKVs(err, kverrors.PreferOuter).Values())
+}
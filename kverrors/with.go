@@ -0,0 +1,98 @@
+package kverrors
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ViaQ/logerr/v2/internal/kv"
+)
+
+// With returns a new error which layers key/value pairs on top of err.
+// Unlike Add, err is never modified so it is safe to annotate errors which are
+// shared between goroutines, like package level errors. The returned error
+// keeps the message, cause, code and stack trace of err and still matches err
+// with errors.Is and errors.As.
+//
+// Example:
+//
+//	var ErrNotFound = kverrors.New("not found")
+//
+//	...
+//
+//	return kverrors.With(ErrNotFound, "name", name)
+func With(err error, keysAndValues ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	parent, ok := err.(*KVError)
+	if !ok {
+		return withStack(annotateForeign(err, keysAndValues), false)
+	}
+
+	return annotate(parent, keysAndValues)
+}
+
+// annotate returns a copy of parent with keysAndValues layered on top
+func annotate(parent *KVError, keysAndValues []interface{}) *KVError {
+	m := make(map[string]interface{}, len(parent.kv)+len(keysAndValues)/2)
+	for k, v := range parent.kv {
+		m[k] = v
+	}
+	for k, v := range kv.ToValueMap(keysAndValues...) {
+		m[k] = v
+	}
+	return &KVError{
+		kv:       m,
+		stack:    parent.stack,
+		template: parent.template,
+		classes:  parent.classes,
+		base:     parent,
+	}
+}
+
+// annotateForeign returns a *KVError with keysAndValues which takes the place
+// of err in the chain. It has the message of err and unwraps to the errors err
+// unwraps to, so key/values of *KVErrors wrapped by err are kept.
+func annotateForeign(err error, keysAndValues []interface{}) *KVError {
+	var e *KVError
+	switch children := Children(err); len(children) {
+	case 0:
+		e = newKVError(err.Error(), keysAndValues)
+	case 1:
+		e = wrapKVError(children[0], LinkMessage(err), keysAndValues)
+	default:
+		e = wrapKVError(multiCause(children), LinkMessage(err), keysAndValues)
+	}
+	e.base = err
+	return e
+}
+
+// multiCause is the cause of an annotated error which unwraps to multiple
+// errors, so the annotation links to the errors directly instead of repeating
+// the annotated error
+type multiCause []error
+
+func (c multiCause) Error() string {
+	msgs := make([]string, len(c))
+	for i, err := range c {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (c multiCause) Unwrap() []error {
+	return c
+}
+
+// WithCtx returns a new error which layers Context on top of err, see With
+func WithCtx(err error, ctx Context) error {
+	return With(err, ctx...)
+}
+
+// As finds the first error in the chain of the error annotated with With
+// that matches target. This is required to work with the standard library
+// errors.As
+func (e *KVError) As(target interface{}) bool {
+	return e.base != nil && errors.As(e.base, target)
+}
@@ -0,0 +1,134 @@
+package kverrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWith_DoesNotModifyParent(t *testing.T) {
+	parent := kverrors.New("not found", "kind", "Pod")
+	err := kverrors.With(parent, "name", "test", "kind", "Node")
+
+	require.Equal(t, map[string]interface{}{
+		kverrors.MessageKey: "not found",
+		"kind":              "Pod",
+	}, kverrors.KVs(parent))
+	require.Equal(t, map[string]interface{}{
+		kverrors.MessageKey: "not found",
+		"kind":              "Node",
+		"name":              "test",
+	}, kverrors.KVs(err))
+	require.Equal(t, "not found", err.Error())
+}
+
+func TestWith_KeepsCause(t *testing.T) {
+	parent := kverrors.Wrap(io.EOF, "failed to read")
+	err := kverrors.WithCtx(parent, kverrors.NewContext("file", "a.json"))
+
+	require.Equal(t, "failed to read: EOF", err.Error())
+	require.Equal(t, io.EOF, kverrors.Unwrap(err))
+	require.Equal(t, "a.json", kverrors.KVs(err)["file"])
+}
+
+func TestWith_Is(t *testing.T) {
+	parent := kverrors.New("not found")
+	err := kverrors.With(kverrors.With(parent, "name", "test"), "namespace", "default")
+
+	require.True(t, errors.Is(err, parent))
+	require.True(t, errors.Is(fmt.Errorf("lookup: %w", err), parent))
+	require.False(t, errors.Is(parent, err))
+}
+
+func TestWith_Template(t *testing.T) {
+	tmpl := kverrors.Define("NotFound", "resource not found")
+	err := kverrors.With(tmpl.New("kind", "Pod"), "name", "test")
+
+	require.True(t, errors.Is(err, tmpl))
+	require.Equal(t, "NotFound", kverrors.Code(err))
+}
+
+func TestWith_NonKVError(t *testing.T) {
+	_, parent := os.Open("does-not-exist")
+	err := kverrors.With(parent, "component", "loader")
+
+	require.Equal(t, parent.Error(), err.Error())
+	require.Equal(t, "loader", kverrors.KVs(err)["component"])
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	var pathErr *os.PathError
+	require.True(t, errors.As(err, &pathErr))
+	require.Equal(t, "does-not-exist", pathErr.Path)
+}
+
+func TestWith_KeepsWrappedChain(t *testing.T) {
+	inner := kverrors.New("inner", "k", "v")
+	foreign := fmt.Errorf("outer: %w", inner)
+	err := kverrors.With(foreign, "a", "b")
+
+	require.Equal(t, "outer: inner", err.Error())
+	require.Equal(t, "outer", kverrors.Message(err))
+	require.Equal(t, inner, kverrors.Root(err))
+	require.True(t, errors.Is(err, foreign))
+	require.Equal(t, map[string]interface{}{"a": "b", "k": "v"}, kverrors.MergeKVs(err, kverrors.PreferOuter).Values())
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `{"a":"b","msg":"outer","cause":{"k":"v","msg":"inner"}}`, string(b))
+}
+
+func TestWith_KeepsJoinedErrors(t *testing.T) {
+	inner := kverrors.New("inner", "k", "v")
+	joined := errors.Join(inner, io.EOF)
+	err := kverrors.With(joined, "a", "b")
+
+	require.Equal(t, "inner\nEOF", err.Error())
+	require.Equal(t, []error{inner, io.EOF}, kverrors.Children(err))
+	require.True(t, errors.Is(err, io.EOF))
+	require.True(t, errors.Is(err, joined))
+	require.Equal(t, map[string]interface{}{"a": "b", "k": "v"}, kverrors.MergeKVs(err, kverrors.PreferOuter).Values())
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `{"a":"b","msg":"inner\nEOF","cause":[{"k":"v","msg":"inner"},"EOF"]}`, string(b))
+}
+
+func TestWith_Nil(t *testing.T) {
+	require.NoError(t, kverrors.With(nil, "key", "value"))
+}
+
+func TestWith_Concurrent(t *testing.T) {
+	parent := kverrors.New("shared", "kind", "Pod")
+
+	errs := make([]error, 50)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := kverrors.With(parent, "worker", i)
+			err = kverrors.With(err, "attempt", i*2)
+
+			_ = err.Error()
+			_, _ = json.Marshal(err)
+			_ = fmt.Sprintf("%+v", err)
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		require.Equal(t, i, kverrors.KVs(err)["worker"])
+		require.Equal(t, i*2, kverrors.KVs(err)["attempt"])
+		require.Equal(t, "Pod", kverrors.KVs(err)["kind"])
+	}
+
+	require.Len(t, kverrors.KVs(parent), 2)
+}
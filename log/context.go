@@ -0,0 +1,42 @@
+package log
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+var (
+	fallbackLock sync.RWMutex
+	fallback     = NewLogger("fallback")
+)
+
+// IntoContext returns a copy of ctx which carries logger
+func IntoContext(ctx context.Context, logger logr.Logger) context.Context {
+	return logr.NewContext(ctx, logger)
+}
+
+// FromContext returns the logger stored in ctx. If ctx does not carry a
+// logger the fallback logger is returned instead, see SetFallbackLogger.
+// Trace correlation fields found in ctx are added to the returned logger,
+// see WithTraceContext.
+func FromContext(ctx context.Context) logr.Logger {
+	logger, err := logr.FromContext(ctx)
+	if err != nil {
+		fallbackLock.RLock()
+		logger = fallback
+		fallbackLock.RUnlock()
+	}
+	return WithTraceContext(ctx, logger)
+}
+
+// SetFallbackLogger sets the logger returned by FromContext when the context
+// does not carry one. By default a logger with the component "fallback"
+// writing to stdout is used.
+func SetFallbackLogger(logger logr.Logger) {
+	fallbackLock.Lock()
+	defer fallbackLock.Unlock()
+
+	fallback = logger
+}
@@ -0,0 +1,33 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext_ReturnsLoggerFromContext(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	ctx := log.IntoContext(context.Background(), log.NewLogger("incontext", log.WithOutput(b)))
+
+	log.FromContext(ctx).Info("hello, world")
+
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:%q`, sink.ComponentKey, "incontext"))
+}
+
+func TestFromContext_ReturnsFallbackLogger(t *testing.T) {
+	previous := log.FromContext(context.Background())
+	t.Cleanup(func() { log.SetFallbackLogger(previous) })
+
+	b := bytes.NewBuffer(nil)
+	log.SetFallbackLogger(log.NewLogger("fallback", log.WithOutput(b)))
+
+	log.FromContext(context.Background()).Info("hello, world")
+
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:%q`, sink.ComponentKey, "fallback"))
+}
@@ -0,0 +1,229 @@
+package decode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/kverrors"
+)
+
+// MaxLineSize is the maximum size of a single log line read by a Decoder
+const MaxLineSize = 16 * 1024 * 1024
+
+// Entry is a structured log line
+type Entry struct {
+	Timestamp time.Time
+	Verbosity int
+	Component string
+	Message   string
+	File      string
+	Line      int
+	// Fields contains all key/value pairs of the log line except the builtin
+	// fields and the error
+	Fields map[string]interface{}
+	// Error is the error of the log line reconstructed with
+	// kverrors.FromValue or nil if there is none. Errors logged with
+	// log.FlattenedErrors are part of Fields.
+	Error error
+	// Raw is the undecoded log line
+	Raw []byte
+}
+
+// FileLine returns the file:line of the entry or an empty string if the
+// line does not contain it
+func (e *Entry) FileLine() string {
+	if e.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", e.File, e.Line)
+}
+
+// LineError is returned by Decoder.Decode for lines which are not JSON
+// objects. Decoding can continue with the next line.
+type LineError struct {
+	// Line is the 1-based number of the line in the stream
+	Line int
+	// Text is the content of the line
+	Text string
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: not a JSON log line: %s", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying parsing error
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads logerr JSON lines from a stream
+type Decoder struct {
+	scanner   *bufio.Scanner
+	line      int
+	useNumber bool
+}
+
+// NewDecoder creates a new Decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineSize)
+	return &Decoder{scanner: scanner}
+}
+
+// UseNumber causes the Decoder to decode numbers as json.Number instead of
+// float64
+func (d *Decoder) UseNumber() {
+	d.useNumber = true
+}
+
+// Decode reads the next entry from the stream. Empty lines are skipped. It
+// returns a *LineError for lines which are not JSON objects and io.EOF at the
+// end of the stream.
+func (d *Decoder) Decode() (*Entry, error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		entry, err := parse(line, d.useNumber)
+		if err != nil {
+			return nil, &LineError{Line: d.line, Text: string(line), Err: err}
+		}
+		return entry, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Parse decodes a single log line
+func Parse(line []byte) (*Entry, error) {
+	return parse(bytes.TrimSpace(line), false)
+}
+
+func parse(line []byte, useNumber bool) (*Entry, error) {
+	var m map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if useNumber {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	if dec.InputOffset() != int64(len(line)) {
+		return nil, kverrors.New("unexpected data after JSON object")
+	}
+	if m == nil {
+		return nil, kverrors.New("log line is not a JSON object")
+	}
+
+	e := &Entry{Raw: append([]byte(nil), line...)}
+	if ts, ok := m[sink.TimeStampKey].(string); ok {
+		e.Timestamp, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	e.Verbosity, _ = strconv.Atoi(fmt.Sprint(m[sink.LevelKey]))
+	e.Component, _ = m[sink.ComponentKey].(string)
+	e.Message, _ = m[sink.MessageKey].(string)
+	if fl, ok := m[sink.FileLineKey].(string); ok {
+		e.File, e.Line = splitFileLine(fl)
+	}
+	if v, ok := m[sink.ErrorKey]; ok {
+		e.Error = decodeError(v)
+	}
+
+	for _, key := range []string{
+		sink.TimeStampKey, sink.FileLineKey, sink.LevelKey,
+		sink.ComponentKey, sink.MessageKey, sink.ErrorKey,
+	} {
+		delete(m, key)
+	}
+	e.Fields = m
+
+	return e, nil
+}
+
+func splitFileLine(fl string) (string, int) {
+	i := strings.LastIndex(fl, ":")
+	if i < 0 {
+		return fl, 0
+	}
+	line, err := strconv.Atoi(fl[i+1:])
+	if err != nil {
+		return fl, 0
+	}
+	return fl[:i], line
+}
+
+// decodeError reconstructs the error of a log line. Errors logged in
+// log.ChainErrors mode are converted to the nested form first.
+func decodeError(v interface{}) error {
+	return kverrors.FromValue(unchain(v))
+}
+
+// unchain converts an error logged in log.ChainErrors mode to the nested form
+// of kverrors.KVError.MarshalJSON. Any other value is returned unchanged.
+func unchain(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	links, ok := m[sink.CausesKey].([]interface{})
+	if !ok || len(links) == 0 {
+		return v
+	}
+
+	var nested interface{}
+	for i := len(links) - 1; i >= 0; i-- {
+		link, ok := links[i].(map[string]interface{})
+		if !ok {
+			return v
+		}
+
+		if errs, ok := link[sink.ErrorsKey].([]interface{}); ok {
+			items := make([]interface{}, 0, len(errs))
+			for _, item := range errs {
+				items = append(items, unchain(item))
+			}
+			nested = items
+			continue
+		}
+
+		if msg, ok := link[kverrors.MessageKey]; ok && len(link) == 1 && nested == nil {
+			nested = msg
+			continue
+		}
+
+		n := make(map[string]interface{}, len(link)+1)
+		for k, lv := range link {
+			n[k] = lv
+		}
+		if nested != nil {
+			n[kverrors.CauseKey] = nested
+		}
+		nested = n
+	}
+
+	// key/values of aggregate items are part of the chain object
+	if msg, ok := nested.(string); ok && len(m) > 2 {
+		nested = map[string]interface{}{kverrors.MessageKey: msg}
+	}
+	if n, ok := nested.(map[string]interface{}); ok {
+		for k, mv := range m {
+			if k != kverrors.MessageKey && k != sink.CausesKey {
+				n[k] = mv
+			}
+		}
+	}
+	return nested
+}
@@ -0,0 +1,164 @@
+package decode_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/ViaQ/logerr/v2/log/decode"
+	"github.com/ViaQ/logerr/v2/log/logtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_Decode(t *testing.T) {
+	in := `{"_ts":"2022-05-01T10:11:12.123456Z","_file:line":"pkg/file.go:42","_level":"2","_component":"comp","_message":"hello","key":"value","count":3}`
+
+	entry, err := decode.NewDecoder(strings.NewReader(in)).Decode()
+	require.NoError(t, err)
+
+	require.Equal(t, time.Date(2022, 5, 1, 10, 11, 12, 123456000, time.UTC), entry.Timestamp)
+	require.Equal(t, 2, entry.Verbosity)
+	require.Equal(t, "comp", entry.Component)
+	require.Equal(t, "hello", entry.Message)
+	require.Equal(t, "pkg/file.go", entry.File)
+	require.Equal(t, 42, entry.Line)
+	require.Equal(t, "pkg/file.go:42", entry.FileLine())
+	require.Equal(t, map[string]interface{}{"key": "value", "count": float64(3)}, entry.Fields)
+	require.Nil(t, entry.Error)
+	require.Equal(t, in, string(entry.Raw))
+}
+
+func TestDecoder_UseNumber(t *testing.T) {
+	dec := decode.NewDecoder(strings.NewReader(`{"_message":"hello","id":12345678901234567890}`))
+	dec.UseNumber()
+
+	entry, err := dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, json.Number("12345678901234567890"), entry.Fields["id"])
+}
+
+func TestDecoder_ReportsNonJSONLines(t *testing.T) {
+	in := strings.Join([]string{
+		`{"_message":"first"}`,
+		`panic: something went wrong`,
+		``,
+		`{"_message":"second"}`,
+		`{"_message":"third"} trailing`,
+		`[1, 2]`,
+	}, "\n")
+	dec := decode.NewDecoder(strings.NewReader(in))
+
+	entry, err := dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, "first", entry.Message)
+
+	_, err = dec.Decode()
+	var lineErr *decode.LineError
+	require.True(t, errors.As(err, &lineErr))
+	require.Equal(t, 2, lineErr.Line)
+	require.Equal(t, "panic: something went wrong", lineErr.Text)
+
+	entry, err = dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, "second", entry.Message)
+
+	_, err = dec.Decode()
+	require.True(t, errors.As(err, &lineErr))
+	require.Equal(t, 5, lineErr.Line)
+
+	_, err = dec.Decode()
+	require.True(t, errors.As(err, &lineErr))
+	require.Equal(t, 6, lineErr.Line)
+
+	_, err = dec.Decode()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestDecoder_ReconstructsError(t *testing.T) {
+	logger, r := logtest.NewLogger("", log.WithVerbosity(2))
+	cause := kverrors.New("inner", "k1", "v1")
+	logger.Error(kverrors.Wrap(cause, "outer", "k2", "v2"), "failed")
+
+	entry, err := decode.Parse(r.Bytes())
+	require.NoError(t, err)
+
+	require.Equal(t, "outer: inner", entry.Error.Error())
+	require.Equal(t, "outer", kverrors.Message(entry.Error))
+	require.EqualValues(t, "v2", kverrors.KVs(entry.Error)["k2"])
+	require.Equal(t, "inner", kverrors.Message(kverrors.Root(entry.Error)))
+	require.EqualValues(t, "v1", kverrors.KVs(kverrors.Root(entry.Error))["k1"])
+	require.NotEmpty(t, entry.File)
+	require.NotZero(t, entry.Line)
+}
+
+func TestDecoder_ReconstructsAggregate(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("is required"), "field", "spec.name")
+	errs.Add(kverrors.New("must not be negative"), "field", "spec.replicas")
+
+	logger, r := logtest.NewLogger("")
+	logger.Error(&errs, "validation failed")
+
+	entry, err := decode.Parse(r.Bytes())
+	require.NoError(t, err)
+
+	var decoded *kverrors.Aggregate
+	require.True(t, errors.As(entry.Error, &decoded))
+	require.Equal(t, 2, decoded.Len())
+	require.EqualValues(t, "spec.replicas", kverrors.KVs(decoded.Items()[1].Err)["field"])
+}
+
+func TestDecoder_ReconstructsForeignCause(t *testing.T) {
+	logger, r := logtest.NewLogger("")
+	logger.Error(kverrors.Wrap(io.ErrUnexpectedEOF, "failed to read", "file", "a.json"), "failed")
+
+	entry, err := decode.Parse(r.Bytes())
+	require.NoError(t, err)
+
+	require.Equal(t, "failed to read: unexpected EOF", entry.Error.Error())
+	require.True(t, errors.Is(entry.Error, io.ErrUnexpectedEOF))
+
+	var foreign *kverrors.ForeignError
+	require.True(t, errors.As(entry.Error, &foreign))
+	require.Equal(t, "unexpected EOF", foreign.Msg)
+}
+
+func TestDecoder_ReconstructsChainErrors(t *testing.T) {
+	var errs kverrors.Aggregate
+	errs.Add(kverrors.New("is required"), "field", "spec.name")
+	errs.Add(fmt.Errorf("read: %w", io.EOF))
+
+	logger, r := logtest.NewLogger("", log.WithErrorMode(log.ChainErrors))
+	logger.Error(kverrors.Wrap(&errs, "invalid spec", "kind", "Pod"), "failed")
+
+	entry, err := decode.Parse(r.Bytes())
+	require.NoError(t, err)
+
+	require.Equal(t, "invalid spec", kverrors.Message(entry.Error))
+	require.EqualValues(t, "Pod", kverrors.KVs(entry.Error)["kind"])
+
+	var decoded *kverrors.Aggregate
+	require.True(t, errors.As(entry.Error, &decoded))
+	require.Equal(t, 2, decoded.Len())
+	require.EqualValues(t, "spec.name", kverrors.KVs(decoded.Items()[0].Err)["field"])
+	require.Equal(t, "read: EOF", decoded.Items()[1].Err.Error())
+	require.True(t, errors.Is(decoded.Items()[1].Err, io.EOF))
+}
+
+func TestDecoder_FlattenedErrors(t *testing.T) {
+	logger, r := logtest.NewLogger("", log.WithErrorMode(log.FlattenedErrors), log.WithErrorPrefix("err."))
+	logger.Error(kverrors.New("failed to read", "file", "a.json"), "failed")
+
+	entry, err := decode.Parse(r.Bytes())
+	require.NoError(t, err)
+
+	require.Nil(t, entry.Error)
+	require.Equal(t, "failed to read", entry.Fields["err.msg"])
+	require.Equal(t, "a.json", entry.Fields["err.file"])
+}
@@ -0,0 +1,21 @@
+// Package decode reads logerr JSON log lines back into structured entries
+//
+// Example:
+//
+//	dec := decode.NewDecoder(os.Stdin)
+//	for {
+//	    entry, err := dec.Decode()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    var lineErr *decode.LineError
+//	    if errors.As(err, &lineErr) {
+//	        // not a JSON log line, continue with the next one
+//	        continue
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(entry.Timestamp, entry.Message)
+//	}
+package decode
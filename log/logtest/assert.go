@@ -0,0 +1,72 @@
+package logtest
+
+import (
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/internal/kv"
+)
+
+// AssertLogged asserts that an entry with msg and all of keysAndValues was
+// recorded. It returns whether the assertion succeeded.
+func AssertLogged(t testing.TB, r *Recorder, msg string, keysAndValues ...interface{}) bool {
+	t.Helper()
+
+	if len(find(r, msg, keysAndValues...)) == 0 {
+		t.Errorf("expected a log entry with message %q and key/values %v, got messages %q", msg, keysAndValues, r.Entries().Messages())
+		return false
+	}
+	return true
+}
+
+// AssertNotLogged asserts that no entry with msg and all of keysAndValues was
+// recorded. It returns whether the assertion succeeded.
+func AssertNotLogged(t testing.TB, r *Recorder, msg string, keysAndValues ...interface{}) bool {
+	t.Helper()
+
+	if found := find(r, msg, keysAndValues...); len(found) > 0 {
+		t.Errorf("expected no log entry with message %q and key/values %v, got %d", msg, keysAndValues, len(found))
+		return false
+	}
+	return true
+}
+
+// AssertErrorLogged asserts that an entry with msg and an error was recorded.
+// It returns whether the assertion succeeded.
+func AssertErrorLogged(t testing.TB, r *Recorder, msg string) bool {
+	t.Helper()
+
+	if len(r.Entries().Errors().WithMessage(msg)) == 0 {
+		t.Errorf("expected an error log entry with message %q, got messages %q", msg, r.Entries().Errors().Messages())
+		return false
+	}
+	return true
+}
+
+// AssertCount asserts that exactly n entries were recorded. It returns whether
+// the assertion succeeded.
+func AssertCount(t testing.TB, r *Recorder, n int) bool {
+	t.Helper()
+
+	if actual := len(r.Entries()); actual != n {
+		t.Errorf("expected %d log entries, got %d", n, actual)
+		return false
+	}
+	return true
+}
+
+// RequireLogged is like AssertLogged but stops the test on failure
+func RequireLogged(t testing.TB, r *Recorder, msg string, keysAndValues ...interface{}) {
+	t.Helper()
+
+	if !AssertLogged(t, r, msg, keysAndValues...) {
+		t.FailNow()
+	}
+}
+
+func find(r *Recorder, msg string, keysAndValues ...interface{}) Entries {
+	found := r.Entries().WithMessage(msg)
+	for k, v := range kv.ToValueMap(keysAndValues...) {
+		found = found.WithKeyValue(k, v)
+	}
+	return found
+}
@@ -0,0 +1,6 @@
+// Package logtest provides helpers to capture and assert logs in tests
+//
+// Loggers created with NewLogger use the regular logerr formatting and
+// record every log call as a structured Entry in a Recorder. Loggers created
+// with New write every log line to the log of a test instead.
+package logtest
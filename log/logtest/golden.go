@@ -0,0 +1,128 @@
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/log/decode"
+)
+
+// Placeholders used by Normalize for values that change between runs
+const (
+	TimestampPlaceholder = "<timestamp>"
+	FileLinePlaceholder  = "<file:line>"
+)
+
+var update = flag.Bool("logtest.update", false, "update logtest golden files")
+
+// Normalize replaces the timestamp and file:line of every JSON log line in b
+// with placeholders and sorts the keys of every line. Lines which are not JSON
+// objects are kept unchanged.
+func Normalize(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+
+	var out bytes.Buffer
+	for _, line := range bytes.Split(bytes.TrimRight(b, "\n"), []byte("\n")) {
+		out.Write(normalizeLine(line))
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+func normalizeLine(line []byte) []byte {
+	var m map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return line
+	}
+	if _, ok := m[sink.TimeStampKey]; ok {
+		m[sink.TimeStampKey] = TimestampPlaceholder
+	}
+	if _, ok := m[sink.FileLineKey]; ok {
+		m[sink.FileLineKey] = FileLinePlaceholder
+	}
+
+	// Encoding the map sorts the keys
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(m); err != nil {
+		return line
+	}
+	return bytes.TrimRight(out.Bytes(), "\n")
+}
+
+// AssertGolden asserts that the output of r matches the golden file at path.
+// Lines are compared as entries decoded with package decode, ignoring their
+// timestamp, file:line and the order of their keys. Run the tests with
+// -logtest.update to write the normalized output to the golden file instead.
+// It returns whether the assertion succeeded.
+func AssertGolden(t testing.TB, r *Recorder, path string) bool {
+	t.Helper()
+
+	output := r.Bytes()
+	actual := Normalize(output)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("failed to create golden file directory: %s", err)
+			return false
+		}
+		if err := ioutil.WriteFile(path, actual, 0o644); err != nil {
+			t.Errorf("failed to update golden file: %s", err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("failed to read golden file: %s", err)
+		return false
+	}
+
+	if !reflect.DeepEqual(goldenEntries(expected), goldenEntries(output)) {
+		t.Errorf("log output does not match golden file %s\nexpected:\n%s\nactual:\n%s", path, Normalize(expected), actual)
+		return false
+	}
+	return true
+}
+
+// goldenEntries decodes the log lines of b without their timestamp, file:line
+// and raw line. Lines which are not JSON objects are kept as an entry with only
+// the raw line.
+func goldenEntries(b []byte) []decode.Entry {
+	var entries []decode.Entry
+	dec := decode.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	for {
+		e, err := dec.Decode()
+		if err == io.EOF {
+			return entries
+		}
+		var lineErr *decode.LineError
+		if errors.As(err, &lineErr) {
+			entries = append(entries, decode.Entry{Raw: []byte(lineErr.Text)})
+			continue
+		}
+		if err != nil {
+			// the rest of the output cannot be decoded, compare it as is
+			return append(entries, decode.Entry{Raw: b})
+		}
+		e.Timestamp, e.File, e.Line, e.Raw = time.Time{}, "", 0, nil
+		entries = append(entries, *e)
+	}
+}
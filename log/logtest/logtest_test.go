@@ -0,0 +1,129 @@
+package logtest_test
+
+import (
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/field"
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/ViaQ/logerr/v2/log/decode"
+	"github.com/ViaQ/logerr/v2/log/logtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecordsEntries(t *testing.T) {
+	logger, r := logtest.NewLogger("mycomponent", log.WithVerbosity(2))
+	err := kverrors.New("an error", "key", "value")
+
+	logger.WithValues("request", "a").Info("hello, world", "count", 3, field.Bool("ok", true))
+	logger.V(1).Info("details")
+	logger.V(1).Error(err, "failed")
+	logger.V(3).Info("disabled")
+
+	entries := r.Entries()
+	require.Len(t, entries, 3)
+
+	require.Equal(t, "mycomponent", entries[0].Component)
+	require.Equal(t, "hello, world", entries[0].Message)
+	require.Equal(t, 0, entries[0].Level)
+	require.Equal(t, map[string]interface{}{"request": "a", "count": 3, "ok": true}, entries[0].Fields)
+	require.Nil(t, entries[0].Error)
+
+	require.Equal(t, 1, entries[1].Level)
+
+	require.Equal(t, 0, entries[2].Level)
+	require.Empty(t, entries[2].Fields)
+	require.Equal(t, err, entries[2].Error)
+
+	line, perr := decode.Parse(entries[2].Raw)
+	require.NoError(t, perr)
+	require.Equal(t, "failed", line.Message)
+	require.NotEmpty(t, line.FileLine())
+	require.Equal(t, string(entries[0].Raw)+"\n"+string(entries[1].Raw)+"\n"+string(entries[2].Raw)+"\n", string(r.Bytes()))
+}
+
+func TestRecorder_Reset(t *testing.T) {
+	logger, r := logtest.NewLogger("")
+
+	logger.Info("hello, world")
+	r.Reset()
+
+	require.Empty(t, r.Entries())
+	require.Empty(t, r.Bytes())
+}
+
+func TestEntries_Queries(t *testing.T) {
+	logger, r := logtest.NewLogger("parent", log.WithVerbosity(1))
+
+	logger.Info("first", "key", "a")
+	logger.WithName("child").Info("second", "key", "b", "other", true)
+	logger.V(1).Info("third")
+	logger.Error(kverrors.New("an error"), "fourth")
+
+	entries := r.Entries()
+	require.Equal(t, []string{"first"}, entries.WithMessage("first").Messages())
+	require.Equal(t, []string{"second"}, entries.WithComponent("parent_child").Messages())
+	require.Equal(t, []string{"first", "second"}, entries.WithKey("key").Messages())
+	require.Equal(t, []string{"second"}, entries.WithKeyValue("key", "b").Messages())
+	require.Equal(t, []string{"second"}, entries.WithKeyValue("other", true).Messages())
+	require.Equal(t, []string{"fourth"}, entries.Errors().Messages())
+	require.Equal(t, []string{"third"}, entries.WithLevel(1).Messages())
+	require.Equal(t, []string{"first", "second", "fourth"}, entries.WithLevel(0).Messages())
+}
+
+func TestAssertions(t *testing.T) {
+	logger, r := logtest.NewLogger("")
+
+	logger.Info("hello, world", "count", 3)
+	logger.Error(kverrors.New("an error"), "failed")
+
+	logtest.AssertLogged(t, r, "hello, world")
+	logtest.AssertLogged(t, r, "hello, world", "count", 3)
+	logtest.AssertNotLogged(t, r, "hello, world", "count", 4)
+	logtest.AssertNotLogged(t, r, "goodbye")
+	logtest.AssertErrorLogged(t, r, "failed")
+	logtest.AssertCount(t, r, 2)
+	logtest.RequireLogged(t, r, "failed")
+
+	ft := &fakeT{TB: t}
+	require.False(t, logtest.AssertLogged(ft, r, "goodbye"))
+	require.False(t, logtest.AssertErrorLogged(ft, r, "hello, world"))
+	require.False(t, logtest.AssertCount(ft, r, 1))
+	require.Equal(t, 3, ft.errors)
+}
+
+func TestNormalize(t *testing.T) {
+	in := []byte(`{"_ts":"2022-01-01T00:00:00Z","_file:line":"a.go:1","_message":"hi","b":1,"a":2}` + "\n" + "not json\n")
+
+	expected := `{"_file:line":"<file:line>","_message":"hi","_ts":"<timestamp>","a":2,"b":1}` + "\n" + "not json\n"
+	require.Equal(t, expected, string(logtest.Normalize(in)))
+}
+
+func TestAssertGolden(t *testing.T) {
+	logger, r := logtest.NewLogger("golden", log.WithVerbosity(2))
+
+	logger.Info("hello, world", "key", "value")
+	logger.Error(kverrors.New("an error", "key", "value"), "failed")
+
+	logtest.AssertGolden(t, r, "testdata/golden.jsonl")
+
+	r.Reset()
+	logger.Info("hello, world", "key", "other")
+	logger.Error(kverrors.New("an error", "key", "value"), "failed")
+
+	ft := &fakeT{TB: t}
+	require.False(t, logtest.AssertGolden(ft, r, "testdata/golden.jsonl"))
+	require.Equal(t, 1, ft.errors)
+}
+
+// fakeT records failures instead of failing the test
+type fakeT struct {
+	testing.TB
+	errors int
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(string, ...interface{}) {
+	f.errors++
+}
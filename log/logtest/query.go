@@ -0,0 +1,104 @@
+package logtest
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Entries is a list of captured entries which can be queried
+type Entries []Entry
+
+// Filter returns the entries for which fn returns true
+func (es Entries) Filter(fn func(Entry) bool) Entries {
+	var res Entries
+	for _, e := range es {
+		if fn(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// WithMessage returns the entries with the message msg
+func (es Entries) WithMessage(msg string) Entries {
+	return es.Filter(func(e Entry) bool {
+		return e.Message == msg
+	})
+}
+
+// WithComponent returns the entries logged by component
+func (es Entries) WithComponent(component string) Entries {
+	return es.Filter(func(e Entry) bool {
+		return e.Component == component
+	})
+}
+
+// WithLevel returns the entries logged with the verbosity level v
+func (es Entries) WithLevel(v int) Entries {
+	return es.Filter(func(e Entry) bool {
+		return e.Level == v
+	})
+}
+
+// WithKey returns the entries which contain the field key
+func (es Entries) WithKey(key string) Entries {
+	return es.Filter(func(e Entry) bool {
+		_, ok := e.Fields[key]
+		return ok
+	})
+}
+
+// WithKeyValue returns the entries which contain the field key with value.
+// Values are compared by their JSON representation, so for example an int
+// matches an int64 and an error its message.
+func (es Entries) WithKeyValue(key string, value interface{}) Entries {
+	return es.Filter(func(e Entry) bool {
+		actual, ok := e.Fields[key]
+		return ok && jsonEqual(actual, value)
+	})
+}
+
+// Errors returns the entries which contain an error
+func (es Entries) Errors() Entries {
+	return es.Filter(func(e Entry) bool {
+		return e.Error != nil
+	})
+}
+
+// Messages returns the messages of all entries
+func (es Entries) Messages() []string {
+	res := make([]string, 0, len(es))
+	for _, e := range es {
+		res = append(res, e.Message)
+	}
+	return res
+}
+
+// jsonEqual compares two values by round-tripping them through encoding/json
+func jsonEqual(a, b interface{}) bool {
+	na, ok := normalize(a)
+	if !ok {
+		return false
+	}
+	nb, ok := normalize(b)
+	return ok && reflect.DeepEqual(na, nb)
+}
+
+// normalize returns v decoded from its JSON representation. Errors which do not
+// implement json.Marshaler are represented by their message.
+func normalize(v interface{}) (interface{}, bool) {
+	if err, ok := v.(error); ok {
+		if _, ok := v.(json.Marshaler); !ok {
+			v = err.Error()
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, false
+	}
+	return normalized, true
+}
@@ -0,0 +1,159 @@
+package logtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ViaQ/logerr/v2/internal/kv"
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/go-logr/logr"
+)
+
+// Entry is a log call captured by a Recorder
+type Entry struct {
+	// Level is the verbosity level of the call. Errors have a level of 0.
+	Level int
+	// Component is the name of the logger
+	Component string
+	Message   string
+	// Fields contains the key/values of the logger and the call. Values of
+	// typed fields, see package field, are unwrapped.
+	Fields map[string]interface{}
+	// Error is the logged error or nil if there is none
+	Error error
+	// Raw is the log line written for the call, see decode.Parse
+	Raw []byte
+}
+
+// NewLogger creates a logger with the provided opts which records every log
+// call in the returned Recorder. The output of opts is ignored.
+func NewLogger(component string, opts ...log.Option) (logr.Logger, *Recorder) {
+	r := &Recorder{}
+	s := &recordSink{
+		recorder: r,
+		sink:     log.NewLogger(component, opts...).GetSink().(*sink.Sink),
+	}
+	return logr.New(s), r
+}
+
+// Recorder captures the log calls of loggers created with NewLogger as
+// entries
+type Recorder struct {
+	mtx     sync.Mutex
+	raw     []byte
+	entries Entries
+}
+
+// Entries returns all entries recorded so far
+func (r *Recorder) Entries() Entries {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return append(Entries(nil), r.entries...)
+}
+
+// Bytes returns the log lines written for all entries recorded so far
+func (r *Recorder) Bytes() []byte {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return append([]byte(nil), r.raw...)
+}
+
+// Reset discards all recorded entries
+func (r *Recorder) Reset() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.raw = nil
+	r.entries = nil
+}
+
+func (r *Recorder) record(e Entry) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.raw = append(r.raw, e.Raw...)
+	r.raw = append(r.raw, '\n')
+	r.entries = append(r.entries, e)
+}
+
+// recordSink records the calls of a logger in recorder and formats their log
+// lines with sink
+type recordSink struct {
+	recorder *Recorder
+	sink     *sink.Sink
+	values   map[string]interface{}
+}
+
+// Init implements logr.LogSink
+func (s *recordSink) Init(info logr.RuntimeInfo) {
+	s.sink.Init(info)
+}
+
+// Enabled implements logr.LogSink
+func (s *recordSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+// Info implements logr.LogSink
+func (s *recordSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if l, ok := s.sink.InfoLine(level, msg, keysAndValues...); ok {
+		s.record(level, l, nil, keysAndValues)
+	}
+}
+
+// Error implements logr.LogSink
+func (s *recordSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.record(0, s.sink.ErrorLine(err, msg, keysAndValues...), err, keysAndValues)
+}
+
+// WithValues implements logr.LogSink
+func (s *recordSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return s.with(s.sink.WithValues(keysAndValues...), keysAndValues)
+}
+
+// WithName implements logr.LogSink
+func (s *recordSink) WithName(name string) logr.LogSink {
+	return s.with(s.sink.WithName(name), nil)
+}
+
+// WithContext adds trace correlation fields like sink.Sink.WithContext. They
+// are part of the log lines but not of the fields of entries.
+func (s *recordSink) WithContext(ctx context.Context) logr.LogSink {
+	return s.with(s.sink.WithContext(ctx), nil)
+}
+
+func (s *recordSink) with(ls logr.LogSink, keysAndValues []interface{}) *recordSink {
+	return &recordSink{recorder: s.recorder, sink: ls.(*sink.Sink), values: s.fields(keysAndValues)}
+}
+
+// fields returns the key/values of the logger combined with keysAndValues
+func (s *recordSink) fields(keysAndValues []interface{}) map[string]interface{} {
+	res := kv.ToValueMap(keysAndValues...)
+	for k, v := range s.values {
+		if _, ok := res[k]; !ok {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+func (s *recordSink) record(level int, l sink.Line, err error, keysAndValues []interface{}) {
+	raw, merr := json.Marshal(l)
+	if merr != nil {
+		raw = []byte(fmt.Sprintf("failed to encode log line %#v: %s", l, merr))
+	}
+
+	s.recorder.record(Entry{
+		Level:     level,
+		Component: l.Component,
+		Message:   l.Message,
+		Fields:    s.fields(keysAndValues),
+		Error:     err,
+		Raw:       raw,
+	})
+}
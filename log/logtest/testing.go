@@ -0,0 +1,127 @@
+package logtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/go-logr/logr"
+)
+
+// LateOutput receives the log lines written after the test of a logger
+// created with New has completed. It defaults to os.Stderr.
+var LateOutput io.Writer = os.Stderr
+
+// New creates a logger with the provided opts which writes every log line to
+// t.Log. The component of the logger is the name of the test. Output is thus
+// only shown if the test fails or -v is set and lines are attributed to the
+// caller of the logger. The output of opts is ignored.
+//
+// Log lines written after the test has completed, for example by goroutines
+// which outlive the test, are written to LateOutput instead.
+func New(t testing.TB, opts ...log.Option) logr.Logger {
+	s := &testSink{
+		t:     t,
+		sink:  log.NewLogger(t.Name(), opts...).GetSink().(*sink.Sink),
+		state: &testState{name: t.Name()},
+	}
+	t.Cleanup(s.state.finish)
+
+	return logr.New(s)
+}
+
+// testState tracks whether the test of a testSink has completed
+type testState struct {
+	mtx  sync.Mutex
+	name string
+	done bool
+}
+
+func (s *testState) finish() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.done = true
+}
+
+// testSink writes the lines formatted by sink to t.Log until the test has
+// completed
+type testSink struct {
+	t     testing.TB
+	sink  *sink.Sink
+	state *testState
+}
+
+var _ logr.CallStackHelperLogSink = &testSink{}
+
+// Init implements logr.LogSink
+func (s *testSink) Init(info logr.RuntimeInfo) {
+	s.sink.Init(info)
+}
+
+// Enabled implements logr.LogSink
+func (s *testSink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+// Info implements logr.LogSink
+func (s *testSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.t.Helper()
+	if l, ok := s.sink.InfoLine(level, msg, keysAndValues...); ok {
+		s.log(l)
+	}
+}
+
+// Error implements logr.LogSink
+func (s *testSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.t.Helper()
+	s.log(s.sink.ErrorLine(err, msg, keysAndValues...))
+}
+
+// WithValues implements logr.LogSink
+func (s *testSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return s.with(s.sink.WithValues(keysAndValues...))
+}
+
+// WithName implements logr.LogSink
+func (s *testSink) WithName(name string) logr.LogSink {
+	return s.with(s.sink.WithName(name))
+}
+
+// WithContext adds trace correlation fields like sink.Sink.WithContext
+func (s *testSink) WithContext(ctx context.Context) logr.LogSink {
+	return s.with(s.sink.WithContext(ctx))
+}
+
+// GetCallStackHelper implements logr.CallStackHelperLogSink
+func (s *testSink) GetCallStackHelper() func() {
+	return s.t.Helper
+}
+
+func (s *testSink) with(ls logr.LogSink) *testSink {
+	return &testSink{t: s.t, sink: ls.(*sink.Sink), state: s.state}
+}
+
+func (s *testSink) log(l sink.Line) {
+	s.t.Helper()
+
+	b, err := json.Marshal(l)
+	if err != nil {
+		b = []byte(fmt.Sprintf("failed to encode log line %#v: %s", l, err))
+	}
+
+	s.state.mtx.Lock()
+	defer s.state.mtx.Unlock()
+
+	if s.state.done {
+		_, _ = fmt.Fprintf(LateOutput, "%s (after test completed): %s\n", s.state.name, b)
+		return
+	}
+	s.t.Log(string(b))
+}
@@ -0,0 +1,84 @@
+package logtest_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/ViaQ/logerr/v2/log/logtest"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_WritesToTestLog(t *testing.T) {
+	lt := &logT{TB: t}
+	logger := logtest.New(lt)
+
+	logger.Info("hello, world", "key", "value")
+
+	require.Len(t, lt.lines, 1)
+	require.Contains(t, lt.lines[0], fmt.Sprintf(`%q:%q`, sink.MessageKey, "hello, world"))
+	require.Contains(t, lt.lines[0], fmt.Sprintf(`%q:%q`, sink.ComponentKey, t.Name()))
+	require.Contains(t, lt.lines[0], fmt.Sprintf(`%q:%q`, "key", "value"))
+}
+
+func TestNew_AttributesLinesToCaller(t *testing.T) {
+	lt := &logT{TB: t}
+	logger := logtest.New(lt, log.WithVerbosity(2))
+
+	_, ok := logger.GetSink().(logr.CallStackHelperLogSink)
+	require.True(t, ok)
+
+	logger.Error(io.EOF, "hello, world")
+
+	require.Len(t, lt.lines, 1)
+	require.Contains(t, lt.lines[0], fmt.Sprintf(`%q:"testing_test.go:`, sink.FileLineKey))
+	require.NotZero(t, lt.helpers)
+}
+
+func TestNew_WritesAfterTestCompleted(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	orig := logtest.LateOutput
+	logtest.LateOutput = b
+	defer func() { logtest.LateOutput = orig }()
+
+	lt := &logT{TB: t}
+	logger := logtest.New(lt)
+	lt.cleanup()
+
+	require.NotPanics(t, func() {
+		logger.Info("too late")
+	})
+	require.Empty(t, lt.lines)
+	require.Contains(t, b.String(), "too late")
+}
+
+// logT records log lines and cleanup functions instead of passing them on
+// to the test
+type logT struct {
+	testing.TB
+	lines    []string
+	cleanups []func()
+	helpers  int
+}
+
+func (l *logT) Helper() {
+	l.helpers++
+}
+
+func (l *logT) Log(args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(args...))
+}
+
+func (l *logT) Cleanup(fn func()) {
+	l.cleanups = append(l.cleanups, fn)
+}
+
+func (l *logT) cleanup() {
+	for _, fn := range l.cleanups {
+		fn()
+	}
+}
@@ -22,3 +22,50 @@ func WithVerbosity(v int) Option {
 		s.SetVerbosity(v)
 	}
 }
+
+// WithTraceExtractor sets the TraceExtractor used to add trace correlation
+// fields to loggers returned by FromContext and WithTraceContext
+func WithTraceExtractor(e TraceExtractor) Option {
+	return func(s *sink.Sink) {
+		s.SetTraceExtractor(e)
+	}
+}
+
+// WithErrorTypes sets whether the Go type of logged errors which are not a
+// *kverrors.KVError, like those created with fmt.Errorf, is recorded
+func WithErrorTypes(enabled bool) Option {
+	return func(s *sink.Sink) {
+		s.SetErrorTypes(enabled)
+	}
+}
+
+// ErrorMode defines how errors are rendered in log lines
+type ErrorMode = sink.ErrorMode
+
+// Error rendering modes, see WithErrorMode
+const (
+	// NestedErrors logs the error as an object under _error with its cause
+	// nested under the cause key. It is the default.
+	NestedErrors = sink.NestedErrors
+	// FlattenedErrors logs the error message and the key/values of the
+	// whole chain as top level fields prefixed with "error.", see
+	// WithErrorPrefix
+	FlattenedErrors = sink.FlattenedErrors
+	// ChainErrors logs the error as an object under _error with the error
+	// message and an array of every error of the chain under causes
+	ChainErrors = sink.ChainErrors
+)
+
+// WithErrorMode sets how errors are rendered in log lines
+func WithErrorMode(mode ErrorMode) Option {
+	return func(s *sink.Sink) {
+		s.SetErrorMode(mode)
+	}
+}
+
+// WithErrorPrefix sets the prefix of error fields in FlattenedErrors mode
+func WithErrorPrefix(prefix string) Option {
+	return func(s *sink.Sink) {
+		s.SetErrorPrefix(prefix)
+	}
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/go-logr/logr"
+)
+
+// PanicMessage is the message of log lines written by HandlePanic
+const PanicMessage = "recovered from panic"
+
+// HandlePanic recovers a panic of the calling goroutine and logs it at error
+// level with keysAndValues as an error created by kverrors.FromPanic. It must
+// be deferred directly. If errp is not nil the panic stops and the error is
+// stored in errp, otherwise the goroutine panics again with the original
+// value after logging.
+//
+// Example:
+//
+//	func (r *Reconciler) Reconcile(ctx context.Context, req Request) (res Result, err error) {
+//	    defer log.HandlePanic(log.FromContext(ctx), &err, "request", req)
+//	    ...
+//	}
+//
+//	go func() {
+//	    defer log.HandlePanic(logger, nil)
+//	    ...
+//	}()
+func HandlePanic(logger logr.Logger, errp *error, keysAndValues ...interface{}) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	err := kverrors.FromPanic(v)
+	logger.Error(err, PanicMessage, keysAndValues...)
+
+	if errp == nil {
+		panic(v)
+	}
+	*errp = err
+}
@@ -0,0 +1,65 @@
+package log_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/kverrors"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/ViaQ/logerr/v2/log/logtest"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func reconcile(logger logr.Logger, v interface{}) (err error) {
+	defer log.HandlePanic(logger, &err, "request", "default/example")
+	panic(v)
+}
+
+func TestHandlePanic_ReturnsError(t *testing.T) {
+	logger, r := logtest.NewLogger("panics")
+
+	err := reconcile(logger, "boom")
+
+	require.True(t, errors.Is(err, kverrors.ErrPanic))
+	require.Equal(t, "boom", kverrors.KVs(err)[kverrors.PanicValueKey])
+	require.Contains(t, kverrors.KVs(err)[kverrors.PanicFuncKey], "log_test.reconcile")
+
+	entries := r.Entries().Errors().WithMessage(log.PanicMessage)
+	require.Len(t, entries, 1)
+	require.Equal(t, "default/example", entries[0].Fields["request"])
+	require.Equal(t, err, entries[0].Error)
+	require.Equal(t, kverrors.ErrPanic.Code(), kverrors.Code(entries[0].Error))
+}
+
+func TestHandlePanic_WrapsErrorValues(t *testing.T) {
+	logger, _ := logtest.NewLogger("panics")
+
+	err := reconcile(logger, io.ErrUnexpectedEOF)
+
+	require.True(t, errors.Is(err, kverrors.ErrPanic))
+	require.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+}
+
+func TestHandlePanic_Repanics(t *testing.T) {
+	logger, r := logtest.NewLogger("panics")
+
+	require.PanicsWithValue(t, "boom", func() {
+		defer log.HandlePanic(logger, nil)
+		panic("boom")
+	})
+	logtest.AssertErrorLogged(t, r, log.PanicMessage)
+}
+
+func TestHandlePanic_NoPanic(t *testing.T) {
+	logger, r := logtest.NewLogger("panics")
+
+	err := func() (err error) {
+		defer log.HandlePanic(logger, &err)
+		return nil
+	}()
+
+	require.NoError(t, err)
+	logtest.AssertCount(t, r, 0)
+}
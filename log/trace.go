@@ -0,0 +1,45 @@
+package log
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/go-logr/logr"
+)
+
+// TraceContext identifies the trace and span a log line belongs to
+type TraceContext = sink.TraceContext
+
+// TraceExtractor extracts the TraceContext carried by a context.Context
+type TraceExtractor = sink.TraceExtractor
+
+// TraceExtractorFunc adapts a function to a TraceExtractor
+type TraceExtractorFunc = sink.TraceExtractorFunc
+
+// TraceParentExtractor extracts the TraceContext from a W3C traceparent value
+// stored with ContextWithTraceParent. It is the default TraceExtractor.
+type TraceParentExtractor = sink.TraceParentExtractor
+
+// ContextWithTraceParent returns a copy of ctx which carries the value of a
+// W3C traceparent header
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return sink.ContextWithTraceParent(ctx, traceParent)
+}
+
+// contextSink is a logr.LogSink which adds trace correlation fields, like
+// *sink.Sink
+type contextSink interface {
+	WithContext(ctx context.Context) logr.LogSink
+}
+
+// WithTraceContext returns a logger which adds the trace_id, span_id and
+// trace_sampled fields found in ctx to every log line. The logger is returned
+// unchanged if ctx carries no trace context or the logger was not created by
+// this package.
+func WithTraceContext(ctx context.Context, logger logr.Logger) logr.Logger {
+	s, ok := logger.GetSink().(contextSink)
+	if !ok {
+		return logger
+	}
+	return logger.WithSink(s.WithContext(ctx))
+}
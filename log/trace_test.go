@@ -0,0 +1,37 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ViaQ/logerr/v2/internal/sink"
+	"github.com/ViaQ/logerr/v2/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext_AddsTraceFields(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	ctx := log.IntoContext(context.Background(), log.NewLogger("traced", log.WithOutput(b)))
+	ctx = log.ContextWithTraceParent(ctx, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	log.FromContext(ctx).Info("hello, world")
+
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:%q`, sink.TraceIDKey, "4bf92f3577b34da6a3ce929d0e0e4736"))
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:%q`, sink.SpanIDKey, "00f067aa0ba902b7"))
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:true`, sink.TraceSampledKey))
+}
+
+func TestWithTraceExtractor(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	extractor := log.TraceExtractorFunc(func(context.Context) (log.TraceContext, bool) {
+		return log.TraceContext{TraceID: "trace", SpanID: "span", Sampled: true}, true
+	})
+	logger := log.NewLogger("traced", log.WithOutput(b), log.WithTraceExtractor(extractor))
+
+	log.WithTraceContext(context.Background(), logger).Info("hello, world")
+
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:%q`, sink.TraceIDKey, "trace"))
+	require.Contains(t, b.String(), fmt.Sprintf(`%q:%q`, sink.SpanIDKey, "span"))
+}